@@ -1,54 +1,453 @@
 package internal_gengo
 
 import (
+	"go/token"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/internal/strs"
 )
 
-// ToCamelCase 将变量名转换为驼峰命名
+// DefaultInitialisms 返回常见缩写词的集合，键为缩写词的大写形式，
+// 将其设置为 CaseConverter.Initialisms 即可启用识别缩写词的转换，例如 user_id -> UserID 而不是 UserId
+// 每次调用都返回一个新的集合，调用方可以向其中添加新的缩写词而不影响其他转换器
+func DefaultInitialisms() map[string]bool {
+	m := make(map[string]bool, len(defaultInitialisms))
+	for k := range defaultInitialisms {
+		m[k] = true
+	}
+	return m
+}
+
+var defaultInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"QPS":   true,
+	"RAM":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"UUID":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
+// CaseConverter 是可配置的命名风格转换器，生成器和外部工具可以共享同一个配置好的实例，
+// 而不必在每次调用时传递选项。零值不识别任何缩写词，也不在字母与数字的交界处拆分单词
+//
+// 所有方法对不含任何字母或数字的输入（例如 ""、"___"、"---"）都返回空字符串，
+// 调用方应检查返回值，而不是将其直接用作标识符；只含数字的输入（例如 "123"）则原样返回，
+// 但 Pascal 会为以数字开头的结果加上前缀 X，见 CaseConverter.Pascal
+type CaseConverter struct {
+	// Initialisms 是缩写词的集合，键为缩写词的大写形式，为 nil 时不识别任何缩写词，
+	// 常见缩写词的集合见 DefaultInitialisms。转换器只读取该集合，调用方不应在使用转换器的同时修改它
+	Initialisms map[string]bool
+
+	// Abbreviations 是复合缩写词的集合，键为缩写词的规范写法，为 nil 时不识别任何复合缩写词
+	// 拆分单词时，从单词开头起不区分大小写地匹配到的最长缩写词会被视为一个完整的单词，
+	// 例如注册 OAuth2 后 OAuth2Token -> oauth2_token，注册 GraphQL 后 GraphQLAPI -> graphql_api；
+	// Pascal 和 Camel 会使用缩写词的规范写法（作为驼峰命名的首个单词时整体小写）
+	Abbreviations map[string]bool
+
+	// SplitDigits 为 true 时，Snake、ScreamingSnake 和 Kebab 会在字母与数字的交界处拆分单词，
+	// 例如 version2 -> version_2；为 false 时只在数字后跟大写字母时拆分，例如 v2Api -> v2_api
+	SplitDigits bool
+
+	// PreserveLeadingUnderscores 为 true 时，Camel、Pascal 和 Kebab 会保留开头的下划线，
+	// 例如 _foo_bar -> _fooBar；Snake 和 ScreamingSnake 总是原样保留下划线
+	PreserveLeadingUnderscores bool
+
+	// PreserveAcronyms 为 true 时，Snake 和 Kebab 会保留由多个大写字母组成的单词的大小写，
+	// 例如 HTTPServer -> HTTP_server，parseURLPath -> parse_URL_path；
+	// 为 false 时所有字母都会被小写，例如 HTTPServer -> http_server
+	PreserveAcronyms bool
+}
+
+// DefaultCaseConverter 是包级转换函数所使用的默认配置，它不识别任何缩写词，
+// 也不在字母与数字的交界处拆分单词，例如 sha256_sum 保持不变；
+// 需要这些行为时请另外构造转换器，例如 CaseConverter{Initialisms: DefaultInitialisms()}
+var DefaultCaseConverter = CaseConverter{}
+
+// ToCamelCase 使用 DefaultCaseConverter 将变量名转换为驼峰命名，见 CaseConverter.Camel
 func ToCamelCase(s string) string {
-	words := strings.FieldsFunc(s, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
-	})
+	return DefaultCaseConverter.Camel(s)
+}
+
+// ToPascalCase 使用 DefaultCaseConverter 将变量名转换为帕斯卡命名，见 CaseConverter.Pascal
+func ToPascalCase(s string) string {
+	return DefaultCaseConverter.Pascal(s)
+}
+
+// ToSnakeCase 使用 DefaultCaseConverter 将变量名转换为下划线命名，见 CaseConverter.Snake
+func ToSnakeCase(s string) string {
+	return DefaultCaseConverter.Snake(s)
+}
+
+// ToScreamingSnakeCase 使用 DefaultCaseConverter 将变量名转换为全大写的下划线命名，
+// 见 CaseConverter.ScreamingSnake
+func ToScreamingSnakeCase(s string) string {
+	return DefaultCaseConverter.ScreamingSnake(s)
+}
+
+// ToKebabCase 使用 DefaultCaseConverter 将变量名转换为短横线命名，见 CaseConverter.Kebab
+func ToKebabCase(s string) string {
+	return DefaultCaseConverter.Kebab(s)
+}
+
+// isInitialism 判断单词是否为 c.Initialisms 中的缩写词
+func (c CaseConverter) isInitialism(word string) bool {
+	return c.Initialisms[strings.ToUpper(word)]
+}
+
+// abbreviation 返回与单词不区分大小写相等的 c.Abbreviations 中的缩写词，不存在时返回空字符串
+func (c CaseConverter) abbreviation(word string) string {
+	for abbr := range c.Abbreviations {
+		if strings.EqualFold(word, abbr) {
+			return abbr
+		}
+	}
+	return ""
+}
+
+// matchAbbreviation 返回从 runes[i] 开始匹配到的最长缩写词的字符数，没有匹配时返回 0
+// 缩写词之后紧跟小写字母时不视为匹配，例如 OAuth 不匹配 OAuthority
+func (c CaseConverter) matchAbbreviation(runes []rune, i int) int {
+	longest := 0
+	for abbr := range c.Abbreviations {
+		n := utf8.RuneCountInString(abbr)
+		if n <= longest || i+n > len(runes) {
+			continue
+		}
+		if i+n < len(runes) && unicode.IsLower(runes[i+n]) {
+			continue
+		}
+		if strings.EqualFold(string(runes[i:i+n]), abbr) {
+			longest = n
+		}
+	}
+	return longest
+}
 
+// Camel 将变量名转换为驼峰命名
+// c.Initialisms 中的缩写词会被整体大写，例如 user_id -> userID，作为首个单词时则整体小写，例如 id_token -> idToken
+// 全大写的单词同样视为缩写词，例如 URL -> url，NASA_mission -> nasaMission
+// 输入中已有的大小写边界也会被识别，例如 HTTPServer -> httpServer，
+// 因此对结果再次调用 Camel 不会改变它
+func (c CaseConverter) Camel(s string) string {
+	prefix, words := c.splitWords(s)
 	if len(words) == 0 {
 		return ""
 	}
-	if len(words[0]) > 0 {
-		words[0] = strings.ToLower(words[0][:1]) + words[0][1:]
+	// 以数字开头的全大写单词（例如 2B）不整体小写，否则 Camel(2_b) = 2B 再次转换时会变为 2b
+	if c.isInitialism(words[0]) || c.abbreviation(words[0]) != "" || (isUpperWord(words[0]) && startsWithUpper(words[0])) {
+		words[0] = strings.ToLower(words[0])
+	} else {
+		words[0] = mapFirstRune(words[0], unicode.ToLower)
 	}
 
 	for i := 1; i < len(words); i++ {
-		words[i] = strings.Title(words[i])
+		words[i] = c.titleWord(words[i])
 	}
-	return strings.Join(words, "")
+	return prefix + strings.Join(words, "")
 }
 
-// ToPascalCase 将变量名转换为帕斯卡命名
-func ToPascalCase(s string) string {
-	words := strings.FieldsFunc(s, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+// Pascal 将变量名转换为帕斯卡命名
+// c.Initialisms 中的缩写词会被整体大写，例如 user_id -> UserID，全大写的单词保持不变，例如 URL_path -> URLPath
+// 结果总是以字母或下划线开头，因此可以直接用作导出的 Go 标识符：以数字开头的结果会加上前缀 X，
+// 与 GoCamelCase 将开头的下划线替换为 X 的做法一致，例如 2item -> X2Item，123 -> X123
+func (c CaseConverter) Pascal(s string) string {
+	prefix, words := c.splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	for i, word := range words {
+		words[i] = c.titleWord(word)
+	}
+	name := prefix + strings.Join(words, "")
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(r) {
+		name = "X" + name
+	}
+	return name
+}
+
+// splitWords 按非字母、非数字的字符拆分变量名，再按与 Snake 相同的大小写和数字边界拆分每一部分，
+// 因此已经是驼峰或帕斯卡命名的输入也能被正确拆分，例如 HTTPServer -> HTTP, Server
+// 若设置了 PreserveLeadingUnderscores，开头的下划线会作为 prefix 单独返回
+func (c CaseConverter) splitWords(s string) (prefix string, words []string) {
+	for _, field := range strings.FieldsFunc(s, isSeparator) {
+		runes := []rune(field)
+		bounds := c.wordBoundaries(runes)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if bounds[i] {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return c.leadingUnderscores(s), words
+}
+
+// leadingUnderscores 在设置了 PreserveLeadingUnderscores 时返回 s 开头的下划线
+func (c CaseConverter) leadingUnderscores(s string) string {
+	if !c.PreserveLeadingUnderscores {
+		return ""
+	}
+	return s[:len(s)-len(strings.TrimLeft(s, "_"))]
+}
+
+// isSeparator 判断字符是否为单词之间的分隔符，即既不是字母也不是数字
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// titleWord 将单词首字母大写，其余字符保持不变，缩写词则整体大写
+func (c CaseConverter) titleWord(word string) string {
+	if c.isInitialism(word) {
+		return strings.ToUpper(word)
+	}
+	if abbr := c.abbreviation(word); abbr != "" {
+		return abbr
+	}
+	return mapFirstRune(word, unicode.ToUpper)
+}
+
+// isUpperWord 判断单词是否包含大写字母且不含小写字母，例如 URL、V2
+func isUpperWord(word string) bool {
+	return strings.IndexFunc(word, unicode.IsUpper) >= 0 && strings.IndexFunc(word, unicode.IsLower) < 0
+}
+
+// mapFirstRune 对单词的首个字符（可能是多字节字符）应用 f，其余字符保持不变
+func mapFirstRune(word string, f func(rune) rune) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if size == 0 {
+		return word
+	}
+	return string(f(r)) + word[size:]
+}
+
+// Snake 将变量名转换为下划线命名
+// 大写字母之前视为单词边界，设置了 SplitDigits 时字母与数字的交界处也视为单词边界，
+// 例如 version2 -> version_2，abc123def -> abc_123_def
+// 连续的大写字母视为一个缩写词，只在其最后一个大写字母后跟小写字母时拆分，例如 HTTPServer -> http_server
+// 数字后的大写字母也只在它开始一个大小写混合的单词时拆分，例如 Int32Value -> int32_value，
+// 而 INT32VALUE -> int32value，因此 ScreamingSnake 的结果再次转换时不会改变
+// 因此若 c.Initialisms 包含该缩写词，Pascal(Snake(x)) 与 Pascal(x) 相同，例如 userIDList -> user_id_list -> UserIDList；
+// 但相邻的缩写词会被合并为一个单词，例如 GetJSONAPI -> get_jsonapi
+// 原有的下划线（包括开头和结尾的下划线）会被原样保留，
+// 若前一个字符已经是下划线，则不会再额外插入下划线，例如 _FooBar -> _foo_bar，user_ID -> user_id，HTTP_Server -> http_server
+func (c CaseConverter) Snake(s string) string {
+	if strings.IndexFunc(s, func(r rune) bool { return !isSeparator(r) }) < 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	runes := []rune(s)
+	bounds := c.wordBoundaries(runes)
+	preserve := false
+	for i, char := range runes {
+		if bounds[i] {
+			builder.WriteRune('_')
+		}
+		if i == 0 || bounds[i] || isSeparator(runes[i-1]) {
+			preserve = c.PreserveAcronyms && isAcronymAt(runes, bounds, i)
+		}
+		if !preserve {
+			char = unicode.ToLower(char)
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// isAcronymAt 判断从 runes[i] 开始的单词是否由多个大写字母组成（可以包含数字），例如 HTTP、UTF8
+func isAcronymAt(runes []rune, bounds []bool, i int) bool {
+	upper := 0
+	for j := i; j < len(runes) && (j == i || !bounds[j]) && !isSeparator(runes[j]); j++ {
+		if unicode.IsLower(runes[j]) {
+			return false
+		}
+		if unicode.IsUpper(runes[j]) {
+			upper++
+		}
+	}
+	return upper > 1
+}
+
+// ScreamingSnake 将变量名转换为全大写的下划线命名，常用于枚举值，例如 myEnumValue -> MY_ENUM_VALUE
+// 单词的拆分规则与 Snake 相同
+func (c CaseConverter) ScreamingSnake(s string) string {
+	return strings.ToUpper(c.Snake(s))
+}
+
+// Kebab 将变量名转换为短横线命名，例如 HTTPServerName -> http-server-name
+// 单词的拆分规则与 Snake 相同，但开头和结尾不会出现短横线，连续的分隔符也会合并为一个
+func (c CaseConverter) Kebab(s string) string {
+	words := strings.FieldsFunc(c.Snake(s), func(r rune) bool {
+		return r == '_' || r == '-'
 	})
+	if len(words) == 0 {
+		return ""
+	}
+	return c.leadingUnderscores(s) + strings.Join(words, "-")
+}
 
-	for i, word := range words {
-		words[i] = strings.Title(word)
+// wordBoundaries 返回 runes 中每个字符是否为一个新单词的开头（首个字符总是 false），
+// 在 isWordBoundary 的基础上，将 c.Abbreviations 中的缩写词视为一个完整的单词
+func (c CaseConverter) wordBoundaries(runes []rune) []bool {
+	bounds := make([]bool, len(runes))
+	fixed := -1 // 由缩写词决定的边界，不再按 isWordBoundary 重新计算
+	for i := 0; i < len(runes); {
+		if i != 0 && i != fixed {
+			bounds[i] = c.isWordBoundary(runes, i)
+		}
+		n := 0
+		if i == 0 || bounds[i] || isSeparator(runes[i-1]) {
+			n = c.matchAbbreviation(runes, i)
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		if i != 0 && !isSeparator(runes[i-1]) {
+			bounds[i] = true
+		}
+		i += n
+		if i < len(runes) {
+			bounds[i] = unicode.IsLetter(runes[i]) || (c.SplitDigits && unicode.IsDigit(runes[i]))
+			fixed = i
+		}
 	}
-	return strings.Join(words, "")
+	return bounds
 }
 
-// ToSnakeCase 将变量名转换为下划线命名
-func ToSnakeCase(s string) string {
+// isWordBoundary 判断 runes[i] 是否为一个新单词的开头
+func (c CaseConverter) isWordBoundary(runes []rune, i int) bool {
+	prev, char := runes[i-1], runes[i]
+	switch {
+	case unicode.IsUpper(char):
+		if unicode.IsLower(prev) {
+			return true
+		}
+		// 连续大写字母中，最后一个大写字母后跟小写字母时，它是下一个单词的开头；
+		// 数字后的大写字母同理，只有它开始一个大小写混合的单词时才拆分（或设置了 SplitDigits），
+		// 这样全大写的单词不会被拆开，例如 INT32VALUE 保持不变，而 Int32Value -> int32_value
+		startsMixed := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsDigit(prev) {
+			return c.SplitDigits || startsMixed
+		}
+		return unicode.IsUpper(prev) && startsMixed
+	case unicode.IsDigit(char):
+		return c.SplitDigits && unicode.IsLetter(prev)
+	case unicode.IsLetter(char):
+		return c.SplitDigits && unicode.IsDigit(prev)
+	}
+	return false
+}
+
+// GoCamelCase 使用与 protoc-gen-go 生成代码完全一致的规则，将 proto 字段名转换为 Go 导出名，
+// 与其他工具生成的代码互通时应使用它而不是 ToPascalCase：
+// 首字母以及下划线后的小写字母会被大写（该下划线被删除），已有的大写字母不会被小写，
+// 开头的下划线会被替换为 X，例如 json_name -> JsonName，foo_3bar -> Foo_3Bar，_foo -> XFoo
+func GoCamelCase(proto string) string {
+	return strs.GoCamelCase(proto)
+}
+
+// GoTypeName 将 proto 消息或枚举的全名转换为 protoc-gen-go 生成的 Go 类型名，
+// 例如 my.pkg.MessageName -> MessageName，开头的点会被忽略，例如 .my.pkg.MessageName -> MessageName
+// 全名中以非大写字母开头的前缀部分被视为包名并删除，直到遇到第一个以大写字母开头的部分，
+// 但最后一部分总会保留，例如 my.pkg.message -> Message；
+// 剩余的嵌套类型名按 GoCamelCase 的规则转换，并以下划线连接，例如 my.pkg.Outer.Inner -> Outer_Inner
+// （与 protoc-gen-go 一致，以小写字母开头的嵌套类型名前不会插入下划线，例如 Outer.inner -> OuterInner）
+// 包名不符合该约定（例如以大写字母开头）时，应先自行删除包名再调用 GoCamelCase
+func GoTypeName(fullName string) string {
+	parts := strings.Split(fullName, ".")
+	i := 0
+	for i < len(parts)-1 && !startsWithUpper(parts[i]) {
+		i++
+	}
+	return GoCamelCase(strings.Join(parts[i:], "."))
+}
+
+// startsWithUpper 判断字符串是否以大写字母开头
+func startsWithUpper(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
+
+// SanitizeGoIdent 确保 name 可以作为 Go 标识符使用：
+// 若 name 是 Go 关键字，则在末尾追加下划线，例如 type -> type_；
+// 若 name 以数字开头，则在开头添加下划线，例如 2fast -> _2fast
+func SanitizeGoIdent(name string) string {
+	if token.IsKeyword(name) {
+		return name + "_"
+	}
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(r) {
+		return "_" + name
+	}
+	return name
+}
+
+// SnakeToCamel 将由单个下划线分隔的小写单词转换为驼峰命名，例如 foo_bar_baz -> fooBarBaz。
+// 它与 CamelToSnake 互为逆运算，但只适用于不含缩写词的简单标识符，
+// 需要识别缩写词时请使用 ToCamelCase。
+func SnakeToCamel(s string) string {
 	var builder strings.Builder
+	upper := false
+	for _, char := range s {
+		if char == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			char = unicode.ToUpper(char)
+			upper = false
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
 
-	for i, char := range s {
+// CamelToSnake 将驼峰命名转换为由单个下划线分隔的小写单词，例如 fooBarBaz -> foo_bar_baz。
+// 它是 SnakeToCamel 的逆运算，每个大写字母都被视为一个新单词的开头，
+// 需要识别缩写词时请使用 ToSnakeCase。
+func CamelToSnake(s string) string {
+	var builder strings.Builder
+	for _, char := range s {
 		if unicode.IsUpper(char) {
-			if i != 0 {
-				builder.WriteRune('_')
-			}
-			builder.WriteRune(unicode.ToLower(char))
-		} else {
-			builder.WriteRune(char)
+			builder.WriteRune('_')
+			char = unicode.ToLower(char)
 		}
+		builder.WriteRune(char)
 	}
 	return builder.String()
 }
@@ -2,6 +2,8 @@ package internal_gengo
 
 import (
 	"fmt"
+	"go/token"
+	"strings"
 	"testing"
 )
 
@@ -20,3 +22,711 @@ func TestConvert(t *testing.T) {
 	}
 
 }
+
+// TestGoNameTagCase pins the output of the package-level converters for
+// Go field names, which the generator uses for the @tag-camel-case,
+// @tag-pascal-case and @tag-snake-case annotations. Changing any of these
+// silently changes the struct tags, and so the JSON or database keys,
+// of regenerated code.
+func TestGoNameTagCase(t *testing.T) {
+	tests := []struct {
+		in, camel, pascal, snake string
+	}{
+		{"Name", "name", "Name", "name"},
+		{"UserId", "userId", "UserId", "user_id"},
+		{"UserID", "userID", "UserID", "user_id"}, // acronym runs are one word in snake case, not user_i_d
+		{"Id", "id", "Id", "id"},
+		{"ApiKey", "apiKey", "ApiKey", "api_key"},
+		{"HttpServer", "httpServer", "HttpServer", "http_server"},
+		{"XmlHttpRequest", "xmlHttpRequest", "XmlHttpRequest", "xml_http_request"},
+		{"OauthToken", "oauthToken", "OauthToken", "oauth_token"},
+		{"AddressLine1", "addressLine1", "AddressLine1", "address_line1"},
+		{"Sha256Sum", "sha256Sum", "Sha256Sum", "sha256_sum"},
+		{"Md5Hash", "md5Hash", "Md5Hash", "md5_hash"},
+		{"Ipv4Address", "ipv4Address", "Ipv4Address", "ipv4_address"},
+		{"OptionalInt32", "optionalInt32", "OptionalInt32", "optional_int32"},
+		{"Int64Value", "int64Value", "Int64Value", "int64_value"},
+		{"V2", "v2", "V2", "v2"},
+		{"Field_1", "field1", "Field1", "field_1"},
+	}
+	for _, tt := range tests {
+		if got := ToCamelCase(tt.in); got != tt.camel {
+			t.Errorf("ToCamelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := ToPascalCase(tt.in); got != tt.pascal {
+			t.Errorf("ToPascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+		if got := ToSnakeCase(tt.in); got != tt.snake {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+	}
+}
+
+func TestSnakeCamelRoundTrip(t *testing.T) {
+	tests := []struct {
+		snake, camel string
+	}{
+		{"", ""},
+		{"foo", "foo"},
+		{"foo_bar", "fooBar"},
+		{"foo_bar_baz", "fooBarBaz"},
+		{"user_id", "userId"},
+		{"http_server_name", "httpServerName"},
+		{"v2_api", "v2Api"},
+		{"a_b_c", "aBC"},
+	}
+	for _, tt := range tests {
+		if got := SnakeToCamel(tt.snake); got != tt.camel {
+			t.Errorf("SnakeToCamel(%q) = %q, want %q", tt.snake, got, tt.camel)
+		}
+		if got := CamelToSnake(tt.camel); got != tt.snake {
+			t.Errorf("CamelToSnake(%q) = %q, want %q", tt.camel, got, tt.snake)
+		}
+		if got := CamelToSnake(SnakeToCamel(tt.snake)); got != tt.snake {
+			t.Errorf("CamelToSnake(SnakeToCamel(%q)) = %q, want %q", tt.snake, got, tt.snake)
+		}
+	}
+}
+
+var snakeCaseTests = []struct {
+	in, want string
+}{
+	{"", ""},
+	{"myVariableName", "my_variable_name"},
+	{"MyVariableName", "my_variable_name"},
+	{"my_variable_name", "my_variable_name"},
+
+	// Leading underscores.
+	{"_foo", "_foo"},
+	{"_FooBar", "_foo_bar"},
+	{"__doubleLeading", "__double_leading"},
+	{"__DoubleLeading", "__double_leading"},
+
+	// Trailing underscores.
+	{"fooBar_", "foo_bar_"},
+	{"fooBar__", "foo_bar__"},
+	{"FooBar_", "foo_bar_"},
+
+	// Doubled underscores.
+	{"foo__bar", "foo__bar"},
+	{"foo__Bar", "foo__bar"},
+	{"foo_Bar", "foo_bar"},
+
+	// Underscores mixed with capitals, as in hand-edited field names.
+	// No underscore is inserted after an existing one.
+	{"user_ID", "user_id"},
+	{"first_Name", "first_name"},
+	{"HTTP_Server", "http_server"},
+	{"user_IDList", "user_id_list"},
+
+	// Digits are not split from adjacent letters by default;
+	// see TestSplitDigits.
+	{"version2", "version2"},
+	{"v2Api", "v2_api"},
+	{"userID2", "user_id2"},
+	{"abc123def", "abc123def"},
+	{"item_2", "item_2"},
+	{"2fast", "2fast"},
+	{"sha256_sum", "sha256_sum"},
+	{"Sha256Sum", "sha256_sum"},
+
+	// Acronyms.
+	{"HTTPServer", "http_server"},
+	{"parseURLPath", "parse_url_path"},
+	{"ID", "id"},
+	{"AAbB", "a_ab_b"},
+	{"getHTTP2Setting", "get_http2_setting"},
+	{"userID", "user_id"},
+}
+
+func TestSplitDigits(t *testing.T) {
+	tests := []struct {
+		in, snake, split string
+	}{
+		{"version2", "version2", "version_2"},
+		{"v2Api", "v2_api", "v_2_api"},
+		{"userID2", "user_id2", "user_id_2"},
+		{"abc123def", "abc123def", "abc_123_def"},
+		{"getHTTP2Setting", "get_http2_setting", "get_http_2_setting"},
+		{"sha256_sum", "sha256_sum", "sha_256_sum"},
+		{"item_2", "item_2", "item_2"},
+	}
+	split := CaseConverter{SplitDigits: true}
+	for _, tt := range tests {
+		if got := (CaseConverter{}).Snake(tt.in); got != tt.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := split.Snake(tt.in); got != tt.split {
+			t.Errorf("Snake(%q) with SplitDigits = %q, want %q", tt.in, got, tt.split)
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for _, tt := range snakeCaseTests {
+		if got := ToSnakeCase(tt.in); got != tt.want {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToScreamingSnakeCase(t *testing.T) {
+	for _, tt := range snakeCaseTests {
+		want := strings.ToUpper(tt.want)
+		if got := ToScreamingSnakeCase(tt.in); got != want {
+			t.Errorf("ToScreamingSnakeCase(%q) = %q, want %q", tt.in, got, want)
+		}
+	}
+	for in, want := range map[string]string{
+		"myEnumValue":    "MY_ENUM_VALUE",
+		"HTTPStatusCode": "HTTP_STATUS_CODE",
+		"MY_ENUM_VALUE":  "MY_ENUM_VALUE",
+		"HTTP2SETTING":   "HTTP2SETTING",
+		"INT32VALUE":     "INT32VALUE",
+		"Int32Value":     "INT32_VALUE",
+	} {
+		if got := ToScreamingSnakeCase(in); got != want {
+			t.Errorf("ToScreamingSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	// Already-screaming names, such as existing enum values, are
+	// returned unchanged: the output of ToScreamingSnakeCase round-trips.
+	for _, tt := range snakeCaseTests {
+		in := strings.ToUpper(tt.want)
+		if got := ToScreamingSnakeCase(in); got != in {
+			t.Errorf("ToScreamingSnakeCase(%q) = %q, want %q", in, got, in)
+		}
+	}
+}
+
+func TestToKebabCase(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"myVariableName", "my-variable-name"},
+		{"MyVariableName", "my-variable-name"},
+		{"my_variable_name", "my-variable-name"},
+		{"my-variable-name", "my-variable-name"},
+		{"_FooBar", "foo-bar"},
+		{"fooBar_", "foo-bar"},
+		{"foo__bar", "foo-bar"},
+		{"HTTPServerName", "http-server-name"},
+		{"parseURLPath", "parse-url-path"},
+		{"userID2", "user-id2"},
+		{"abc123def", "abc123def"},
+		{"v2Api", "v2-api"},
+	}
+	for _, tt := range tests {
+		if got := ToKebabCase(tt.in); got != tt.want {
+			t.Errorf("ToKebabCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInitialisms(t *testing.T) {
+	tests := []struct {
+		in, camel, pascal string
+	}{
+		// Single-word initialisms.
+		{"id", "id", "ID"},
+		{"url", "url", "URL"},
+		{"http", "http", "HTTP"},
+
+		// Initialisms mid-name or at the end.
+		{"user_id", "userID", "UserID"},
+		{"parse_url_path", "parseURLPath", "ParseURLPath"},
+		{"get_json_api", "getJSONAPI", "GetJSONAPI"},
+
+		// Initialisms at the start.
+		{"id_token", "idToken", "IDToken"},
+		{"http_server", "httpServer", "HTTPServer"},
+		{"uuid_list", "uuidList", "UUIDList"},
+
+		// All-caps input words.
+		{"URL", "url", "URL"},
+		{"ID", "id", "ID"},
+		{"URL_path", "urlPath", "URLPath"},
+		{"URLPath", "urlPath", "URLPath"},
+
+		// Not initialisms.
+		{"identity", "identity", "Identity"},
+		{"my_variable_name", "myVariableName", "MyVariableName"},
+	}
+	c := CaseConverter{Initialisms: DefaultInitialisms()}
+	for _, tt := range tests {
+		if got := c.Camel(tt.in); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestInitialismsExtend(t *testing.T) {
+	c := CaseConverter{Initialisms: DefaultInitialisms()}
+	if got, want := c.Pascal("grpc_client"), "GrpcClient"; got != want {
+		t.Errorf("Pascal(%q) = %q, want %q", "grpc_client", got, want)
+	}
+	c.Initialisms["GRPC"] = true
+	if got, want := c.Pascal("grpc_client"), "GRPCClient"; got != want {
+		t.Errorf("Pascal(%q) = %q, want %q", "grpc_client", got, want)
+	}
+
+	// Extending one converter's set does not affect other converters.
+	if DefaultInitialisms()["GRPC"] {
+		t.Errorf("DefaultInitialisms() includes an initialism added to a previously returned set")
+	}
+	if got, want := ToPascalCase("grpc_client"), "GrpcClient"; got != want {
+		t.Errorf("ToPascalCase(%q) = %q, want %q", "grpc_client", got, want)
+	}
+}
+
+func TestCaseConverterCustomInitialisms(t *testing.T) {
+	c := CaseConverter{
+		Initialisms: map[string]bool{"GRPC": true, "DB": true},
+		SplitDigits: true,
+	}
+	tests := []struct {
+		in, camel, pascal string
+	}{
+		{"grpc_client", "grpcClient", "GRPCClient"},
+		{"open_db", "openDB", "OpenDB"},
+		{"db_grpc_conn", "dbGRPCConn", "DBGRPCConn"},
+		// Only the configured set is recognized.
+		{"user_id", "userId", "UserId"},
+	}
+	for _, tt := range tests {
+		if got := c.Camel(tt.in); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestCaseConverterOptions(t *testing.T) {
+	tests := []struct {
+		c             CaseConverter
+		in            string
+		snake, kebab  string
+		screaming     string
+		camel, pascal string
+	}{{
+		c:  CaseConverter{SplitDigits: true},
+		in: "v2Api", snake: "v_2_api", kebab: "v-2-api", screaming: "V_2_API",
+		camel: "v2Api", pascal: "V2Api",
+	}, {
+		c:  CaseConverter{},
+		in: "v2Api", snake: "v2_api", kebab: "v2-api", screaming: "V2_API",
+		camel: "v2Api", pascal: "V2Api",
+	}, {
+		c:  CaseConverter{},
+		in: "version2", snake: "version2", kebab: "version2", screaming: "VERSION2",
+		camel: "version2", pascal: "Version2",
+	}, {
+		c:  CaseConverter{},
+		in: "_foo_bar", snake: "_foo_bar", kebab: "foo-bar", screaming: "_FOO_BAR",
+		camel: "fooBar", pascal: "FooBar",
+	}, {
+		c:  CaseConverter{PreserveLeadingUnderscores: true},
+		in: "__foo_bar", snake: "__foo_bar", kebab: "__foo-bar", screaming: "__FOO_BAR",
+		camel: "__fooBar", pascal: "__FooBar",
+	}, {
+		c:  CaseConverter{PreserveLeadingUnderscores: true},
+		in: "fooBar", snake: "foo_bar", kebab: "foo-bar", screaming: "FOO_BAR",
+		camel: "fooBar", pascal: "FooBar",
+	}, {
+		c:  CaseConverter{},
+		in: "HTTPServer", snake: "http_server", kebab: "http-server", screaming: "HTTP_SERVER",
+		camel: "httpServer", pascal: "HTTPServer",
+	}, {
+		c:  CaseConverter{PreserveAcronyms: true},
+		in: "HTTPServer", snake: "HTTP_server", kebab: "HTTP-server", screaming: "HTTP_SERVER",
+		camel: "httpServer", pascal: "HTTPServer",
+	}, {
+		c:  CaseConverter{},
+		in: "parseURLPath", snake: "parse_url_path", kebab: "parse-url-path", screaming: "PARSE_URL_PATH",
+		camel: "parseURLPath", pascal: "ParseURLPath",
+	}, {
+		c:  CaseConverter{PreserveAcronyms: true},
+		in: "parseURLPath", snake: "parse_URL_path", kebab: "parse-URL-path", screaming: "PARSE_URL_PATH",
+		camel: "parseURLPath", pascal: "ParseURLPath",
+	}, {
+		c:  CaseConverter{PreserveAcronyms: true},
+		in: "getA_UTF8Value", snake: "get_a_UTF8_value", kebab: "get-a-UTF8-value", screaming: "GET_A_UTF8_VALUE",
+		camel: "getAUTF8Value", pascal: "GetAUTF8Value",
+	}}
+	for _, tt := range tests {
+		if got := tt.c.Snake(tt.in); got != tt.snake {
+			t.Errorf("%+v.Snake(%q) = %q, want %q", tt.c, tt.in, got, tt.snake)
+		}
+		if got := tt.c.Kebab(tt.in); got != tt.kebab {
+			t.Errorf("%+v.Kebab(%q) = %q, want %q", tt.c, tt.in, got, tt.kebab)
+		}
+		if got := tt.c.ScreamingSnake(tt.in); got != tt.screaming {
+			t.Errorf("%+v.ScreamingSnake(%q) = %q, want %q", tt.c, tt.in, got, tt.screaming)
+		}
+		if got := tt.c.Camel(tt.in); got != tt.camel {
+			t.Errorf("%+v.Camel(%q) = %q, want %q", tt.c, tt.in, got, tt.camel)
+		}
+		if got := tt.c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("%+v.Pascal(%q) = %q, want %q", tt.c, tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestTitleCaseUnicode(t *testing.T) {
+	tests := []struct {
+		in, camel, pascal string
+	}{
+		// Multi-byte leading runes.
+		{"über_wert", "überWert", "ÜberWert"},
+		{"ÜBER_wert", "überWert", "ÜBERWert"},
+		{"état_ñandú", "étatÑandú", "ÉtatÑandú"},
+
+		// All-caps words are not lowercased, except as the leading word
+		// of a camel-case name, where they are treated as initialisms.
+		{"get_FOO_bar", "getFOOBar", "GetFOOBar"},
+		{"NASA_mission", "nasaMission", "NASAMission"},
+	}
+	for _, tt := range tests {
+		if got := ToCamelCase(tt.in); got != tt.camel {
+			t.Errorf("ToCamelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := ToPascalCase(tt.in); got != tt.pascal {
+			t.Errorf("ToPascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestPascalDigits(t *testing.T) {
+	tests := []struct {
+		c      CaseConverter
+		in     string
+		pascal string
+	}{
+		// Trailing digits.
+		{DefaultCaseConverter, "item_2", "Item2"},
+		{DefaultCaseConverter, "item2", "Item2"},
+
+		// Interior digits.
+		{DefaultCaseConverter, "item_2_name", "Item2Name"},
+		{DefaultCaseConverter, "v2_api", "V2Api"},
+		{CaseConverter{Initialisms: DefaultInitialisms()}, "v2_api", "V2API"},
+
+		// Leading digits are prefixed with X.
+		{DefaultCaseConverter, "2item", "X2item"},
+		{DefaultCaseConverter, "2_item", "X2Item"},
+		{DefaultCaseConverter, "__2item", "X2item"},
+		{CaseConverter{SplitDigits: true}, "2item", "X2Item"},
+		{CaseConverter{PreserveLeadingUnderscores: true}, "_2item", "_2item"},
+	}
+	for _, tt := range tests {
+		got := tt.c.Pascal(tt.in)
+		if got != tt.pascal {
+			t.Errorf("%+v.Pascal(%q) = %q, want %q", tt.c, tt.in, got, tt.pascal)
+		}
+		if !token.IsIdentifier(got) {
+			t.Errorf("%+v.Pascal(%q) = %q, which is not a valid Go identifier", tt.c, tt.in, got)
+		}
+	}
+}
+
+func TestSanitizeGoIdent(t *testing.T) {
+	keywords := []string{
+		"break", "case", "chan", "const", "continue",
+		"default", "defer", "else", "fallthrough", "for",
+		"func", "go", "goto", "if", "import",
+		"interface", "map", "package", "range", "return",
+		"select", "struct", "switch", "type", "var",
+	}
+	for _, kw := range keywords {
+		if got, want := SanitizeGoIdent(kw), kw+"_"; got != want {
+			t.Errorf("SanitizeGoIdent(%q) = %q, want %q", kw, got, want)
+		}
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"2fast", "_2fast"},
+		{"3DPoint", "_3DPoint"},
+		{"0", "_0"},
+		{"Type", "Type"},
+		{"types", "types"},
+		{"field2", "field2"},
+		{"_1", "_1"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeGoIdent(tt.in); got != tt.want {
+			t.Errorf("SanitizeGoIdent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoCamelCase(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"foo", "Foo"},
+		{"foo_bar", "FooBar"},
+		{"json_name", "JsonName"},
+		{"user_id", "UserId"},
+		{"foo_3bar", "Foo_3Bar"},
+		{"_my_field_name_2", "XMyFieldName_2"},
+		{"my_Name", "My_Name"},
+		{"camelCase", "CamelCase"},
+		{"SCREAMING_SNAKE_CASE", "SCREAMING_SNAKE_CASE"},
+		{"double__underscore", "Double_Underscore"},
+		{"go2proto", "Go2Proto"},
+	}
+	for _, tt := range tests {
+		if got := GoCamelCase(tt.in); got != tt.want {
+			t.Errorf("GoCamelCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoTypeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		// Top-level types.
+		{"MessageName", "MessageName"},
+		{"my.pkg.MessageName", "MessageName"},
+		{".my.pkg.MessageName", "MessageName"},
+		{"google.protobuf.Any", "Any"},
+		{"my.pkg.message_name", "MessageName"},
+
+		// Nested types.
+		{"my.pkg.Outer.Inner", "Outer_Inner"},
+		{"my.pkg.Outer.Middle.Inner", "Outer_Middle_Inner"},
+		{"Outer.Inner", "Outer_Inner"},
+		{"my.pkg.Outer.inner_type", "OuterInnerType"},
+		{"my.pkg.Outer.Inner_Enum", "Outer_Inner_Enum"},
+	}
+	for _, tt := range tests {
+		if got := GoTypeName(tt.in); got != tt.want {
+			t.Errorf("GoTypeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyWordInputs(t *testing.T) {
+	tests := []struct {
+		in, camel, pascal, snake, kebab string
+	}{
+		{"", "", "", "", ""},
+		{"___", "", "", "", ""},
+		{"---", "", "", "", ""},
+		{"_-_", "", "", "", ""},
+		{"123", "123", "X123", "123", "123"},
+		{"a", "a", "A", "a", "a"},
+	}
+	converters := []CaseConverter{
+		DefaultCaseConverter,
+		{PreserveLeadingUnderscores: true},
+	}
+	for _, c := range converters {
+		for _, tt := range tests {
+			if got := c.Camel(tt.in); got != tt.camel {
+				t.Errorf("%+v.Camel(%q) = %q, want %q", c, tt.in, got, tt.camel)
+			}
+			if got := c.Pascal(tt.in); got != tt.pascal {
+				t.Errorf("%+v.Pascal(%q) = %q, want %q", c, tt.in, got, tt.pascal)
+			}
+			if got := c.Snake(tt.in); got != tt.snake {
+				t.Errorf("%+v.Snake(%q) = %q, want %q", c, tt.in, got, tt.snake)
+			}
+			if got, want := c.ScreamingSnake(tt.in), strings.ToUpper(tt.snake); got != want {
+				t.Errorf("%+v.ScreamingSnake(%q) = %q, want %q", c, tt.in, got, want)
+			}
+			if got := c.Kebab(tt.in); got != tt.kebab {
+				t.Errorf("%+v.Kebab(%q) = %q, want %q", c, tt.in, got, tt.kebab)
+			}
+		}
+	}
+}
+
+func TestConvertIdempotent(t *testing.T) {
+	inputs := []string{
+		"",
+		"a",
+		"123",
+		"my_variable_name",
+		"myVariableName",
+		"MyVariableName",
+		"HTTPServer",
+		"httpServer",
+		"parse_url_path",
+		"parseURLPath",
+		"xmlHTTPRequest",
+		"getHTTP2Setting",
+		"id_token",
+		"IDToken",
+		"userID2",
+		"v2Api",
+		"version2",
+		"NASA_mission",
+		"URL",
+		"URL_path",
+		"über_wert",
+		"ABc",
+		"__foo_bar",
+		"foo--bar__baz",
+
+		// Digits followed by letters, which must not be split
+		// once the letters have been uppercased.
+		"int32value",
+		"Int32Value",
+		"utf8string",
+		"sha256sum",
+		"Sha256Sum",
+		"v2api",
+		"2b",
+		"2_b",
+		"HTTP2SETTING",
+	}
+	// Every short string over an alphabet of lowercase and uppercase
+	// letters, digits, and separators, so that fixed examples cannot
+	// hide a boundary rule that is not stable.
+	alphabet := []string{"a", "B", "2", "_", "-"}
+	short := []string{""}
+	for i := 0; i < len(short); i++ {
+		if len(short[i]) < 4 {
+			for _, a := range alphabet {
+				short = append(short, short[i]+a)
+			}
+		}
+	}
+	converters := []struct {
+		name string
+		f    func(string) string
+	}{
+		{"ToCamelCase", ToCamelCase},
+		{"ToPascalCase", ToPascalCase},
+		{"ToSnakeCase", ToSnakeCase},
+		{"ToScreamingSnakeCase", ToScreamingSnakeCase},
+		{"ToKebabCase", ToKebabCase},
+	}
+	for _, cc := range []CaseConverter{{}, {SplitDigits: true}} {
+		converters = append(converters, []struct {
+			name string
+			f    func(string) string
+		}{
+			{fmt.Sprintf("%+v.Camel", cc), cc.Camel},
+			{fmt.Sprintf("%+v.Snake", cc), cc.Snake},
+			{fmt.Sprintf("%+v.ScreamingSnake", cc), cc.ScreamingSnake},
+			{fmt.Sprintf("%+v.Kebab", cc), cc.Kebab},
+		}...)
+	}
+	for _, c := range converters {
+		for _, in := range append(inputs, short...) {
+			once := c.f(in)
+			if twice := c.f(once); twice != once {
+				t.Errorf("%s(%q) = %q, but %s(%q) = %q", c.name, in, once, c.name, once, twice)
+			}
+		}
+	}
+
+	c := CaseConverter{Initialisms: DefaultInitialisms()}
+	for _, tt := range []struct {
+		in, camel, pascal string
+	}{
+		{"HTTPServer", "httpServer", "HTTPServer"},
+		{"myVariableName", "myVariableName", "MyVariableName"},
+		{"xmlHTTPRequest", "xmlHTTPRequest", "XMLHTTPRequest"},
+		{"userIdToken", "userIDToken", "UserIDToken"},
+	} {
+		if got := c.Camel(tt.in); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestSnakePascalRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, snake, pascal string
+	}{
+		{"getID", "get_id", "GetID"},
+		{"parseURL", "parse_url", "ParseURL"},
+		{"httpServer2", "http_server2", "HTTPServer2"},
+		{"userIDList", "user_id_list", "UserIDList"},
+		{"HTTPServer", "http_server", "HTTPServer"},
+		{"myVariableName", "my_variable_name", "MyVariableName"},
+	}
+	c := CaseConverter{Initialisms: DefaultInitialisms()}
+	for _, tt := range tests {
+		snake := c.Snake(tt.in)
+		if snake != tt.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tt.in, snake, tt.snake)
+		}
+		if got := c.Pascal(snake); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", snake, got, tt.pascal)
+		}
+		if got := c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
+
+func TestAbbreviations(t *testing.T) {
+	c := CaseConverter{
+		Initialisms:   DefaultInitialisms(),
+		Abbreviations: map[string]bool{"OAuth": true, "OAuth2": true, "GraphQL": true},
+		SplitDigits:   true,
+	}
+	tests := []struct {
+		in, snake, camel, pascal string
+	}{
+		{"OAuth2Token", "oauth2_token", "oauth2Token", "OAuth2Token"},
+		{"OAuthToken", "oauth_token", "oauthToken", "OAuthToken"},
+		{"GraphQLAPI", "graphql_api", "graphqlAPI", "GraphQLAPI"},
+		{"myGraphQLQuery", "my_graphql_query", "myGraphQLQuery", "MyGraphQLQuery"},
+		{"oauth2_token", "oauth2_token", "oauth2Token", "OAuth2Token"},
+		{"graphql_server", "graphql_server", "graphqlServer", "GraphQLServer"},
+
+		// Abbreviations only match whole words.
+		{"OAuthority", "o_authority", "oAuthority", "OAuthority"},
+		{"oauthtoken", "oauthtoken", "oauthtoken", "Oauthtoken"},
+	}
+	for _, tt := range tests {
+		if got := c.Snake(tt.in); got != tt.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := c.Camel(tt.in); got != tt.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := c.Pascal(tt.in); got != tt.pascal {
+			t.Errorf("Pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+		if got := c.Pascal(c.Snake(tt.in)); got != c.Pascal(tt.in) {
+			t.Errorf("Pascal(Snake(%q)) = %q, want %q", tt.in, got, c.Pascal(tt.in))
+		}
+	}
+}
+
+func TestAbbreviationsRegister(t *testing.T) {
+	var c CaseConverter
+	if got, want := c.Snake("OAuth2Token"), "o_auth2_token"; got != want {
+		t.Errorf("Snake(%q) = %q, want %q", "OAuth2Token", got, want)
+	}
+	c.Abbreviations = map[string]bool{"OAuth2": true}
+	if got, want := c.Snake("OAuth2Token"), "oauth2_token"; got != want {
+		t.Errorf("Snake(%q) = %q, want %q", "OAuth2Token", got, want)
+	}
+
+	// Other converters, including the default one, are unaffected.
+	if got, want := ToSnakeCase("OAuth2Token"), "o_auth2_token"; got != want {
+		t.Errorf("ToSnakeCase(%q) = %q, want %q", "OAuth2Token", got, want)
+	}
+}
@@ -47,6 +47,31 @@ func MarshalTo(w io.Writer, m proto.Message) (int, error) {
 	return MarshalOptions{}.MarshalTo(w, m)
 }
 
+// MarshalDelimited returns the varint size-delimited wire-format encoding of m.
+func (o MarshalOptions) MarshalDelimited(m proto.Message) ([]byte, error) {
+	size := o.MarshalOptions.Size(m)
+	b := make([]byte, 0, protowire.SizeVarint(uint64(size))+size)
+	b = protowire.AppendVarint(b, uint64(size))
+	prefix := len(b)
+	b, err := o.MarshalOptions.MarshalAppend(b, m)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(b) - prefix; n != size {
+		// The size prefix has already been written, so the encoding is unusable.
+		return nil, errors.New("message size changed from %d to %d during marshaling", size, n)
+	}
+	return b, nil
+}
+
+// MarshalDelimited returns the varint size-delimited wire-format encoding of m
+// with the default options.
+//
+// See the documentation for [MarshalOptions.MarshalDelimited].
+func MarshalDelimited(m proto.Message) ([]byte, error) {
+	return MarshalOptions{}.MarshalDelimited(m)
+}
+
 // UnmarshalOptions is a configurable varint size-delimited unmarshaler.
 type UnmarshalOptions struct {
 	proto.UnmarshalOptions
@@ -16,6 +16,7 @@ import (
 	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/testprotos/test3"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
@@ -72,6 +73,67 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestMarshalDelimited(t *testing.T) {
+	msgs := []*test3.TestAllTypes{
+		{},
+		{SingularInt32: 1},
+		{SingularString: "hello"},
+		{RepeatedBytes: [][]byte{bytes.Repeat([]byte{'a'}, 300)}},
+	}
+	for _, m := range msgs {
+		b, err := protodelim.MarshalDelimited(m)
+		if err != nil {
+			t.Fatalf("protodelim.MarshalDelimited(%v) = %v", m, err)
+		}
+
+		size, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			t.Fatalf("protodelim.MarshalDelimited(%v): invalid size prefix: %v", m, protowire.ParseError(n))
+		}
+		if got, want := size, uint64(len(b)-n); got != want {
+			t.Errorf("protodelim.MarshalDelimited(%v): size prefix = %d, want body length %d", m, got, want)
+		}
+
+		buf := &bytes.Buffer{}
+		if _, err := protodelim.MarshalTo(buf, m); err != nil {
+			t.Fatalf("protodelim.MarshalTo(_, %v) = %v", m, err)
+		}
+		if !bytes.Equal(b, buf.Bytes()) {
+			t.Errorf("protodelim.MarshalDelimited(%v) = %x, want MarshalTo output %x", m, b, buf.Bytes())
+		}
+
+		got := &test3.TestAllTypes{}
+		if err := protodelim.UnmarshalFrom(bufio.NewReader(bytes.NewReader(b)), got); err != nil {
+			t.Fatalf("protodelim.UnmarshalFrom(_) = %v", err)
+		}
+		if diff := cmp.Diff(m, got, protocmp.Transform()); diff != "" {
+			t.Errorf("round trip of MarshalDelimited(%v): diff -want +got = %s", m, diff)
+		}
+	}
+}
+
+func TestMarshalDelimitedAllocs(t *testing.T) {
+	// The size prefix and the message are written into a single buffer,
+	// so MarshalDelimited allocates no more than sizing the message
+	// and marshaling it into a preallocated buffer.
+	m := &test3.TestAllTypes{SingularString: "hello", RepeatedInt32: []int32{1, 2, 3}}
+	const count = 100
+	marshalAllocs := testing.AllocsPerRun(count, func() {
+		b := make([]byte, 0, proto.Size(m))
+		if _, err := (proto.MarshalOptions{}).MarshalAppend(b, m); err != nil {
+			t.Fatal(err)
+		}
+	})
+	delimitedAllocs := testing.AllocsPerRun(count, func() {
+		if _, err := protodelim.MarshalDelimited(m); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if delimitedAllocs > marshalAllocs {
+		t.Errorf("MarshalDelimited: %v allocs/op, want at most the %v of a single MarshalAppend", delimitedAllocs, marshalAllocs)
+	}
+}
+
 // Just a wrapper so that UnmarshalFrom doesn't recognize this as a bufio.Reader
 type notBufioReader struct {
 	*bufio.Reader
@@ -130,6 +130,34 @@ func (ls *listReflect) AppendMutable() protoreflect.Value {
 func (ls *listReflect) Truncate(i int) {
 	ls.v.Elem().Set(ls.v.Elem().Slice(0, i))
 }
+func (ls *listReflect) Grow(n int) {
+	if ls.v.IsNil() {
+		return
+	}
+	s := ls.v.Elem()
+	if s.Cap()-s.Len() < n {
+		ns := reflect.MakeSlice(s.Type(), s.Len(), s.Len()+n)
+		reflect.Copy(ns, s)
+		s.Set(ns)
+	}
+}
+func (ls *listReflect) Int64At(i int) (int64, bool) {
+	s, ok := ls.v.Interface().(*[]int64)
+	if !ok || s == nil {
+		return 0, false
+	}
+	return (*s)[i], true
+}
+func (ls *listReflect) AppendInt64s(b []int64) ([]int64, bool) {
+	s, ok := ls.v.Interface().(*[]int64)
+	if !ok {
+		return b, false
+	}
+	if s == nil {
+		return b, true
+	}
+	return append(b, *s...), true
+}
 func (ls *listReflect) NewElement() protoreflect.Value {
 	return ls.conv.New()
 }
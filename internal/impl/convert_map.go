@@ -113,6 +113,19 @@ func (ms *mapReflect) Range(f func(protoreflect.MapKey, protoreflect.Value) bool
 func (ms *mapReflect) NewValue() protoreflect.Value {
 	return ms.valConv.New()
 }
+func (ms *mapReflect) NewKey() protoreflect.MapKey {
+	return ms.keyConv.Zero().MapKey()
+}
+func (ms *mapReflect) Grow(n int) {
+	// Go maps cannot be grown in place. Moving the entries to a larger map
+	// costs as much as the growth it avoids, so only an empty map is replaced
+	// by one sized for n entries. This is only possible if the map is settable,
+	// which is the case for maps obtained through Message.Mutable.
+	if ms.v.Len() != 0 || !ms.v.CanSet() {
+		return
+	}
+	ms.v.Set(reflect.MakeMapWithSize(ms.v.Type(), n))
+}
 func (ms *mapReflect) IsValid() bool {
 	return !ms.v.IsNil()
 }
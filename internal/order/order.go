@@ -5,6 +5,8 @@
 package order
 
 import (
+	"sort"
+
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -87,3 +89,18 @@ var (
 		}
 	}
 )
+
+// EnumValue is the name and number of a declared enum value.
+type EnumValue struct {
+	Name   protoreflect.Name
+	Number protoreflect.EnumNumber
+}
+
+// SortEnumValues sorts enum values by number.
+// Aliases sharing the same number retain their relative order,
+// such that values provided in declaration order remain so among aliases.
+func SortEnumValues(values []EnumValue) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Number < values[j].Number
+	})
+}
@@ -172,3 +172,28 @@ func TestKeyOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestSortEnumValues(t *testing.T) {
+	values := []EnumValue{
+		{"THREE", 3},
+		{"ONE", 1},
+		{"UNO", 1},
+		{"ZERO", 0},
+		{"TRES", 3},
+		{"NEGATIVE", -1},
+		{"YI", 1},
+	}
+	want := []EnumValue{
+		{"NEGATIVE", -1},
+		{"ZERO", 0},
+		{"ONE", 1},
+		{"UNO", 1},
+		{"YI", 1},
+		{"THREE", 3},
+		{"TRES", 3},
+	}
+	SortEnumValues(values)
+	if diff := cmp.Diff(want, values); diff != "" {
+		t.Errorf("SortEnumValues() mismatch (-want +got):\n%v", diff)
+	}
+}
@@ -68,6 +68,31 @@ func (o UnmarshalOptions) Unmarshal(b []byte, m Message) error {
 	return err
 }
 
+// UnmarshalWithOffsets is like [UnmarshalOptions.Unmarshal], but also reports
+// the byte offset within b of the tag of every top-level field of m,
+// keyed by field number and listed in the order the fields appear in b.
+// Fields of nested messages are not reported.
+// No offsets are reported for a message using the MessageSet wire format,
+// since its top-level fields are items rather than fields of the message.
+func (o UnmarshalOptions) UnmarshalWithOffsets(b []byte, m Message) (map[protoreflect.FieldNumber][]int, error) {
+	if err := o.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	offsets := make(map[protoreflect.FieldNumber][]int)
+	if messageset.IsMessageSet(m.ProtoReflect().Descriptor()) {
+		return offsets, nil
+	}
+	for off := 0; off < len(b); {
+		num, _, n := protowire.ConsumeField(b[off:])
+		if n < 0 {
+			return nil, errDecode
+		}
+		offsets[num] = append(offsets[num], off)
+		off += n
+	}
+	return offsets, nil
+}
+
 // UnmarshalState parses a wire-format message and places the result in m.
 //
 // This method permits fine-grained control over the unmarshaler.
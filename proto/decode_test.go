@@ -11,12 +11,16 @@ import (
 	"testing"
 
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/testing/protopack"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/flags"
+	messagesetpb "google.golang.org/protobuf/internal/testprotos/messageset/messagesetpb"
+	msetextpb "google.golang.org/protobuf/internal/testprotos/messageset/msetextpb"
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
 	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
 )
@@ -107,6 +111,80 @@ func TestDecodeZeroLengthBytes(t *testing.T) {
 	}
 }
 
+func TestDecodeFieldOffsets(t *testing.T) {
+	fields := []protopack.Message{
+		{protopack.Tag{1, protopack.VarintType}, protopack.Varint(5)},
+		{protopack.Tag{14, protopack.BytesType}, protopack.String("hello")},
+		{protopack.Tag{18, protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{1, protopack.VarintType}, protopack.Varint(7),
+		}},
+		{protopack.Tag{31, protopack.VarintType}, protopack.Varint(1)},
+		{protopack.Tag{31, protopack.VarintType}, protopack.Varint(2)},
+		{protopack.Tag{50000, protopack.VarintType}, protopack.Varint(3)},
+		{protopack.Tag{1, protopack.VarintType}, protopack.Varint(6)},
+	}
+	var wire []byte
+	want := make(map[protoreflect.FieldNumber][]int)
+	for _, f := range fields {
+		num, _, _ := protowire.ConsumeTag(f.Marshal())
+		want[num] = append(want[num], len(wire))
+		wire = append(wire, f.Marshal()...)
+	}
+
+	m := &testpb.TestAllTypes{}
+	got, err := proto.UnmarshalOptions{}.UnmarshalWithOffsets(wire, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded offsets = %v, want %v", got, want)
+	}
+	for num, offsets := range got {
+		for _, off := range offsets {
+			if n, _, _ := protowire.ConsumeTag(wire[off:]); n != num {
+				t.Errorf("offset %d for field %d points at tag for field %d", off, num, n)
+			}
+		}
+	}
+	if m.GetOptionalInt32() != 6 || m.GetOptionalNestedMessage().GetA() != 7 || len(m.GetRepeatedInt32()) != 2 {
+		t.Errorf("UnmarshalWithOffsets produced %v", m)
+	}
+}
+
+func TestDecodeFieldOffsetsInvalid(t *testing.T) {
+	wire := protopack.Message{
+		protopack.Tag{1, protopack.VarintType}, protopack.Varint(5),
+		protopack.Tag{14, protopack.BytesType}, protopack.Bytes("truncated"),
+	}.Marshal()
+	got, err := proto.UnmarshalOptions{}.UnmarshalWithOffsets(wire[:len(wire)-1], &testpb.TestAllTypes{})
+	if err == nil {
+		t.Errorf("UnmarshalWithOffsets of truncated input = %v, want error", got)
+	}
+}
+
+func TestDecodeFieldOffsetsMessageSet(t *testing.T) {
+	if !flags.ProtoLegacy {
+		t.Skip("MessageSet requires the protolegacy build tag")
+	}
+	m := &messagesetpb.MessageSet{}
+	proto.SetExtension(m, msetextpb.E_Ext1_MessageSetExtension, &msetextpb.Ext1{Ext1Field1: proto.Int32(10)})
+	wire, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &messagesetpb.MessageSet{}
+	offsets, err := proto.UnmarshalOptions{}.UnmarshalWithOffsets(wire, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offsets) != 0 {
+		t.Errorf("UnmarshalWithOffsets of a MessageSet reported offsets %v, want none", offsets)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("UnmarshalWithOffsets produced %v, want %v", got, m)
+	}
+}
+
 func TestDecodeOneofNilWrapper(t *testing.T) {
 	wire := protopack.Message{
 		protopack.Tag{111, protopack.VarintType}, protopack.Varint(1111),
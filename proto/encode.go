@@ -73,6 +73,14 @@ type MarshalOptions struct {
 	// There is absolutely no guarantee that Size followed by Marshal with
 	// UseCachedSize set will perform equivalently to Marshal alone.
 	UseCachedSize bool
+
+	// DiscardUnknown specifies whether to omit unknown fields from the output,
+	// including those of any nested messages.
+	// The unknown fields remain stored in the message itself.
+	// Size computed with the same options accounts for the omitted fields.
+	//
+	// Setting this option bypasses any fast-path marshal implementation.
+	DiscardUnknown bool
 }
 
 // flags turns the specified MarshalOptions (user-facing) into
@@ -173,7 +181,7 @@ func (o MarshalOptions) MarshalState(in protoiface.MarshalInput) (protoiface.Mar
 func (o MarshalOptions) marshal(b []byte, m protoreflect.Message) (out protoiface.MarshalOutput, err error) {
 	allowPartial := o.AllowPartial
 	o.AllowPartial = true
-	if methods := protoMethods(m); methods != nil && methods.Marshal != nil &&
+	if methods := protoMethods(m); methods != nil && methods.Marshal != nil && !o.DiscardUnknown &&
 		!(o.Deterministic && methods.Flags&protoiface.SupportMarshalDeterministic == 0) {
 		in := protoiface.MarshalInput{
 			Message: m,
@@ -258,7 +266,9 @@ func (o MarshalOptions) marshalMessageSlow(b []byte, m protoreflect.Message) ([]
 	if err != nil {
 		return b, err
 	}
-	b = append(b, m.GetUnknown()...)
+	if !o.DiscardUnknown {
+		b = append(b, m.GetUnknown()...)
+	}
 	return b, nil
 }
 
@@ -17,6 +17,7 @@ import (
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protopack"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"google.golang.org/protobuf/internal/errors"
@@ -110,6 +111,45 @@ func TestEncodeRequiredFieldChecks(t *testing.T) {
 	}
 }
 
+func TestEncodeDiscardUnknown(t *testing.T) {
+	unknown := protopack.Message{
+		protopack.Tag{Number: 50000, Type: protopack.VarintType}, protopack.Uvarint(100),
+	}.Marshal()
+	nested := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+	nested.ProtoReflect().SetUnknown(unknown)
+	m := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalNestedMessage: nested,
+	}
+	m.ProtoReflect().SetUnknown(unknown)
+
+	want, err := proto.Marshal(&testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+	})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	opts := proto.MarshalOptions{DiscardUnknown: true}
+	got, err := opts.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalOptions{DiscardUnknown: true}.Marshal() = %x, want %x", got, want)
+	}
+	if bytes.Contains(got, unknown) {
+		t.Errorf("MarshalOptions{DiscardUnknown: true}.Marshal() output contains unknown fields: %x", got)
+	}
+	if size := opts.Size(m); size != len(got) {
+		t.Errorf("MarshalOptions{DiscardUnknown: true}.Size() = %v, want %v", size, len(got))
+	}
+	if !bytes.Equal(m.ProtoReflect().GetUnknown(), unknown) {
+		t.Errorf("Marshal modified unknown fields of the source message")
+	}
+}
+
 func TestEncodeAppend(t *testing.T) {
 	want := []byte("prefix")
 	got := append([]byte(nil), want...)
@@ -24,6 +24,9 @@ import (
 //   - Message fields are equal if they have
 //     the same set of populated known and extension field values, and
 //     the same set of unknown fields values.
+//     Unknown fields are compared after grouping them by field number,
+//     so the relative order of unknown fields with different numbers
+//     does not matter, but the order of those with the same number does.
 //
 //   - Lists are equal if they are the same length and
 //     each corresponding element is equal.
@@ -987,6 +987,40 @@ func TestEqual(t *testing.T) {
 				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
 			}.Marshal())),
 			y: &testeditionspb.TestAllTypes{},
+		}, {
+			// Unknown fields with different numbers may be stored in any order.
+			x: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{100001, protopack.BytesType}, protopack.String("a"),
+			}.Marshal())),
+			y: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100001, protopack.BytesType}, protopack.String("a"),
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+			}.Marshal())),
+			eq: true,
+		}, {
+			// Unknown fields with the same number may be split by other fields.
+			x: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(2),
+				protopack.Tag{100001, protopack.BytesType}, protopack.String("a"),
+			}.Marshal())),
+			y: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{100001, protopack.BytesType}, protopack.String("a"),
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(2),
+			}.Marshal())),
+			eq: true,
+		}, {
+			// The relative order of unknown fields with the same number matters.
+			x: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(2),
+			}.Marshal())),
+			y: build(&testpb.TestAllTypes{}, unknown(protopack.Message{
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(2),
+				protopack.Tag{100000, protopack.VarintType}, protopack.Varint(1),
+			}.Marshal())),
 		},
 	}
 
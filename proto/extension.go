@@ -5,6 +5,8 @@
 package proto
 
 import (
+	"sort"
+
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -93,3 +95,23 @@ func RangeExtensions(m Message, f func(protoreflect.ExtensionType, interface{})
 		return true
 	})
 }
+
+// PopulatedExtensions returns the field numbers of every populated extension
+// field in m, sorted in ascending order.
+// Regular fields are not included, even if populated.
+func PopulatedExtensions(m Message) []protoreflect.FieldNumber {
+	// Treat nil message interface as an empty message; no populated fields.
+	if m == nil {
+		return nil
+	}
+
+	var nums []protoreflect.FieldNumber
+	m.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() {
+			nums = append(nums, fd.Number())
+		}
+		return true
+	})
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums
+}
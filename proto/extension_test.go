@@ -7,6 +7,7 @@ package proto_test
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 
@@ -350,6 +351,41 @@ func TestExtensionRanger(t *testing.T) {
 	}
 }
 
+func TestPopulatedExtensions(t *testing.T) {
+	m := &descpb.MessageOptions{
+		Deprecated:          proto.Bool(true),
+		MapEntry:            proto.Bool(false),
+		UninterpretedOption: []*descpb.UninterpretedOption{{}},
+	}
+	if got := proto.PopulatedExtensions(m); len(got) != 0 {
+		t.Errorf("proto.PopulatedExtensions(%v) = %v, want none", m, got)
+	}
+
+	xts := []protoreflect.ExtensionType{
+		test3pb.E_OptionalStringExt,
+		test3pb.E_OptionalInt32Ext,
+		test3pb.E_OptionalForeignEnumExt,
+	}
+	proto.SetExtension(m, test3pb.E_OptionalStringExt, "hello")
+	proto.SetExtension(m, test3pb.E_OptionalInt32Ext, int32(5))
+	proto.SetExtension(m, test3pb.E_OptionalForeignEnumExt, test3pb.ForeignEnum_FOREIGN_BAR)
+
+	var want []protoreflect.FieldNumber
+	for _, xt := range xts {
+		want = append(want, xt.TypeDescriptor().Number())
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+	got := proto.PopulatedExtensions(m)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("proto.PopulatedExtensions mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := proto.PopulatedExtensions(nil); got != nil {
+		t.Errorf("proto.PopulatedExtensions(nil) = %v, want nil", got)
+	}
+}
+
 func TestExtensionGetRace(t *testing.T) {
 	// Concurrently fetch an extension value while marshaling the message containing it.
 	// Create the message with proto.Unmarshal to give lazy extension decoding (if present)
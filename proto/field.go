@@ -0,0 +1,65 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// HasField reports whether the field with the given full name is populated in m.
+// The name is either the full name of a field declared in the message,
+// such as "pkg.Message.field", or the full name of an extension of the message
+// registered in [protoregistry.GlobalTypes].
+// It panics if name does not identify a field of m.
+func HasField(m Message, name protoreflect.FullName) bool {
+	mr := m.ProtoReflect()
+	return mr.Has(fieldByFullName(mr, name))
+}
+
+// ClearField clears the field with the given full name in m.
+// See [HasField] for how the name is resolved.
+// It panics if name does not identify a field of m.
+func ClearField(m Message, name protoreflect.FullName) {
+	mr := m.ProtoReflect()
+	mr.Clear(fieldByFullName(mr, name))
+}
+
+// GetField retrieves the value of the field with the given full name in m.
+// See [HasField] for how the name is resolved, and
+// [protoreflect.Message.Get] for the value returned for unpopulated fields.
+// It panics if name does not identify a field of m.
+func GetField(m Message, name protoreflect.FullName) protoreflect.Value {
+	mr := m.ProtoReflect()
+	return mr.Get(fieldByFullName(mr, name))
+}
+
+// SetField stores the value of the field with the given full name in m.
+// See [HasField] for how the name is resolved.
+// It panics if name does not identify a field of m,
+// or under the same conditions as [protoreflect.Message.Set].
+func SetField(m Message, name protoreflect.FullName, v protoreflect.Value) {
+	mr := m.ProtoReflect()
+	mr.Set(fieldByFullName(mr, name), v)
+}
+
+// fieldByFullName resolves name to a field declared in m
+// or to a registered extension of m.
+func fieldByFullName(m protoreflect.Message, name protoreflect.FullName) protoreflect.FieldDescriptor {
+	md := m.Descriptor()
+	if name.Parent() == md.FullName() {
+		if fd := md.Fields().ByName(name.Name()); fd != nil {
+			return fd
+		}
+	}
+	if xt, err := protoregistry.GlobalTypes.FindExtensionByName(name); err == nil {
+		if xd := xt.TypeDescriptor(); xd.ContainingMessage().FullName() == md.FullName() {
+			return xd
+		}
+	}
+	panic(fmt.Sprintf("message %v has no field %v", md.FullName(), name))
+}
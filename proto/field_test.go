@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestFieldByFullName(t *testing.T) {
+	tests := []struct {
+		m     proto.Message
+		name  protoreflect.FullName
+		value protoreflect.Value
+	}{
+		{&testpb.TestAllTypes{}, "goproto.proto.test.TestAllTypes.optional_int32", protoreflect.ValueOfInt32(5)},
+		{&testpb.TestAllTypes{}, "goproto.proto.test.TestAllTypes.oneof_string", protoreflect.ValueOfString("s")},
+		{&testpb.TestAllExtensions{}, "goproto.proto.test.optional_int32", protoreflect.ValueOfInt32(5)},
+		{&testpb.TestAllExtensions{}, "goproto.proto.test.optional_string", protoreflect.ValueOfString("s")},
+	}
+	for _, tt := range tests {
+		if proto.HasField(tt.m, tt.name) {
+			t.Errorf("HasField(%v) = true before SetField, want false", tt.name)
+		}
+		proto.SetField(tt.m, tt.name, tt.value)
+		if !proto.HasField(tt.m, tt.name) {
+			t.Errorf("HasField(%v) = false after SetField, want true", tt.name)
+		}
+		if got := proto.GetField(tt.m, tt.name); !got.Equal(tt.value) {
+			t.Errorf("GetField(%v) = %v, want %v", tt.name, got, tt.value)
+		}
+		proto.ClearField(tt.m, tt.name)
+		if proto.HasField(tt.m, tt.name) {
+			t.Errorf("HasField(%v) = true after ClearField, want false", tt.name)
+		}
+	}
+}
+
+func TestFieldByFullNamePanics(t *testing.T) {
+	for _, name := range []protoreflect.FullName{
+		"goproto.proto.test.TestAllTypes.no_such_field",
+		"goproto.proto.test.TestAllTypes",
+		"optional_int32",
+		"goproto.proto.test.ForeignMessage.c",
+		// An extension of a different message.
+		"goproto.proto.test.optional_int32",
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GetField(%v) did not panic", name)
+				}
+			}()
+			proto.GetField(&testpb.TestAllTypes{}, name)
+		}()
+	}
+}
@@ -21,7 +21,9 @@ func (o MarshalOptions) sizeMessageSet(m protoreflect.Message) (size int) {
 		size += protowire.SizeBytes(o.size(v.Message()))
 		return true
 	})
-	size += messageset.SizeUnknown(m.GetUnknown())
+	if !o.DiscardUnknown {
+		size += messageset.SizeUnknown(m.GetUnknown())
+	}
 	return size
 }
 
@@ -38,7 +40,7 @@ func (o MarshalOptions) marshalMessageSet(b []byte, m protoreflect.Message) ([]b
 		b, err = o.marshalMessageSetField(b, fd, v)
 		return err == nil
 	})
-	if err != nil {
+	if err != nil || o.DiscardUnknown {
 		return b, err
 	}
 	return messageset.AppendUnknown(b, m.GetUnknown())
@@ -0,0 +1,242 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetPath retrieves the value at the given field path in m.
+//
+// A path is a non-empty sequence of segments separated by '.',
+// such as "outer.inner.value". Each segment is the name of a field
+// declared in the message reached by the preceding segments,
+// as written in the .proto file; extension fields cannot be named.
+// Every segment except the last must refer to a message.
+//
+// A segment naming a repeated or map field may be followed by
+// a subscript in square brackets that selects a single element:
+//
+//   - For a repeated field, the subscript is a decimal index
+//     in the range [0, len), such as "items[2]".
+//   - For a map field with string keys, the subscript is
+//     a Go string literal, such as `entries["k"]`.
+//   - For a map field with integer keys, the subscript is
+//     a decimal integer, such as "counts[-1]".
+//   - For a map field with bool keys, the subscript is true or false.
+//
+// A repeated or map field without a subscript refers to the entire
+// [protoreflect.List] or [protoreflect.Map], and so may only be
+// the last segment. Whitespace is not permitted anywhere in the path.
+//
+// Unpopulated fields along the path are read as by
+// [protoreflect.Message.Get], so the result may be a default value.
+// It reports an error if the path is malformed, names an unknown field,
+// continues past a value that is not a message, or selects an index
+// out of range or a map key that is not present.
+//
+// This is the only parser of the path syntax: SetPath, and GetByPath and
+// RepeatedLen in package [google.golang.org/protobuf/reflect/protopath],
+// all resolve paths in the same way.
+func GetPath(m Message, path string) (protoreflect.Value, error) {
+	_, v, err := resolvePath(m.ProtoReflect(), path, false)
+	return v, err
+}
+
+// SetPath stores v at the given field path in m.
+// The path is interpreted as by [GetPath], except that the map key
+// selected by the last segment need not be present, and map entries
+// selected by other segments are created as needed.
+//
+// Unpopulated message fields along the path are populated as by
+// [protoreflect.Message.Mutable]. A subscript on the last segment replaces
+// an existing list element or stores a map entry; lists are never extended.
+// It reports an error, without modifying m, under the same conditions
+// as [GetPath] other than a missing map key.
+// It panics if v cannot be stored in the selected field, element,
+// or entry under the same conditions as [protoreflect.Message.Set].
+func SetPath(m Message, path string, v protoreflect.Value) error {
+	mr := m.ProtoReflect()
+	steps, _, err := resolvePath(mr, path, true)
+	if err != nil {
+		return err
+	}
+	for i, s := range steps {
+		last := i == len(steps)-1
+		switch {
+		case !s.subscript:
+			if last {
+				mr.Set(s.fd, v)
+				return nil
+			}
+			mr = mr.Mutable(s.fd).Message()
+		case s.fd.IsList():
+			l := mr.Mutable(s.fd).List()
+			if last {
+				l.Set(s.index, v)
+				return nil
+			}
+			mr = l.Get(s.index).Message()
+		default:
+			mp := mr.Mutable(s.fd).Map()
+			if last {
+				mp.Set(s.key, v)
+				return nil
+			}
+			mr = mp.Mutable(s.key).Message()
+		}
+	}
+	return nil
+}
+
+// pathStep is a resolved segment of a field path.
+type pathStep struct {
+	fd        protoreflect.FieldDescriptor
+	subscript bool
+	index     int                 // for a subscripted list
+	key       protoreflect.MapKey // for a subscripted map
+}
+
+// resolvePath resolves every segment of path in m without modifying it,
+// and returns the value it refers to. If missingKeys is set,
+// map keys that are not present are not reported as errors;
+// the returned value is invalid if the last key is not present.
+func resolvePath(m protoreflect.Message, path string, missingKeys bool) ([]pathStep, protoreflect.Value, error) {
+	var steps []pathStep
+	var v protoreflect.Value
+	rest := path
+	for {
+		s, tail, err := resolvePathSegment(m, path, rest)
+		if err != nil {
+			return nil, protoreflect.Value{}, err
+		}
+		v = m.Get(s.fd)
+		switch {
+		case !s.subscript:
+		case s.fd.IsList():
+			if s.index >= v.List().Len() {
+				return nil, protoreflect.Value{}, errors.New("invalid path %q: index %d out of range for field %v of length %d", path, s.index, s.fd.Name(), v.List().Len())
+			}
+			v = v.List().Get(s.index)
+		default:
+			mp := v.Map()
+			switch {
+			case mp.Has(s.key):
+				v = mp.Get(s.key)
+			case !missingKeys:
+				return nil, protoreflect.Value{}, errors.New("invalid path %q: key %v not present in field %v", path, s.key, s.fd.Name())
+			case tail != "":
+				// The entry will be created by SetPath,
+				// so continue resolving in a new empty message.
+				v = mp.NewValue()
+			default:
+				v = protoreflect.Value{}
+			}
+		}
+		steps = append(steps, s)
+		if tail == "" {
+			return steps, v, nil
+		}
+		if !v.IsMessage() {
+			return nil, protoreflect.Value{}, errors.New("invalid path %q: %v is not a message", path, path[:len(path)-len(tail)])
+		}
+		m = v.Message()
+		rest = tail[len("."):]
+	}
+}
+
+// resolvePathSegment resolves the leading segment of rest,
+// a suffix of path, to a field of m.
+// It returns the resolved step and the remainder of rest,
+// which is either empty or begins with a '.'.
+func resolvePathSegment(m protoreflect.Message, path, rest string) (s pathStep, tail string, err error) {
+	n := strings.IndexAny(rest, ".[")
+	if n < 0 {
+		n = len(rest)
+	}
+	name := protoreflect.Name(rest[:n])
+	if !name.IsValid() {
+		return s, "", errors.New("invalid path %q: invalid field name %q", path, name)
+	}
+	md := m.Descriptor()
+	s.fd = md.Fields().ByName(name)
+	if s.fd == nil {
+		return s, "", errors.New("invalid path %q: message %v has no field %v", path, md.FullName(), name)
+	}
+	tail = rest[n:]
+
+	if strings.HasPrefix(tail, "[") {
+		sub, ok := "", false
+		if strings.HasPrefix(tail, `["`) {
+			sub, _ = strconv.QuotedPrefix(tail[len("["):])
+		} else if n := strings.IndexByte(tail, ']'); n > 0 {
+			sub = tail[len("["):n]
+		}
+		tail = tail[len("[")+len(sub):]
+		if sub == "" || !strings.HasPrefix(tail, "]") {
+			return s, "", errors.New("invalid path %q: malformed subscript for field %v", path, name)
+		}
+		tail = tail[len("]"):]
+		s.subscript = true
+		switch {
+		case s.fd.IsList():
+			s.index, err = strconv.Atoi(sub)
+			ok = err == nil && s.index >= 0 && sub[0] != '+'
+		case s.fd.IsMap():
+			s.key, ok = parsePathKey(s.fd.MapKey().Kind(), sub)
+		default:
+			return s, "", errors.New("invalid path %q: subscript on field %v which is not a repeated or map field", path, name)
+		}
+		if !ok {
+			return s, "", errors.New("invalid path %q: invalid subscript %s for field %v", path, sub, name)
+		}
+	}
+
+	if tail != "" && tail[0] != '.' {
+		return s, "", errors.New("invalid path %q: unexpected %q after field %v", path, tail[:1], name)
+	}
+	return s, tail, nil
+}
+
+// parsePathKey parses a map key of the given kind from a path subscript.
+func parsePathKey(k protoreflect.Kind, s string) (protoreflect.MapKey, bool) {
+	switch k {
+	case protoreflect.BoolKind:
+		switch s {
+		case "true":
+			return protoreflect.ValueOfBool(true).MapKey(), true
+		case "false":
+			return protoreflect.ValueOfBool(false).MapKey(), true
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(n)).MapKey(), true
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(n).MapKey(), true
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(n)).MapKey(), true
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(n).MapKey(), true
+		}
+	case protoreflect.StringKind:
+		if !strings.HasPrefix(s, `"`) {
+			break
+		}
+		if s, err := strconv.Unquote(s); err == nil {
+			return protoreflect.ValueOfString(s).MapKey(), true
+		}
+	}
+	return protoreflect.MapKey{}, false
+}
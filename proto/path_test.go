@@ -0,0 +1,236 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func newPathMessage() *testpb.TestAllTypes {
+	return &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalString: proto.String("deep"),
+			},
+		},
+		RepeatedInt32: []int32{10, 11, 12},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(20)},
+			{A: proto.Int32(21)},
+		},
+		MapInt32Int32:   map[int32]int32{-1: 30},
+		MapUint64Uint64: map[uint64]uint64{1 << 40: 31},
+		MapBoolBool:     map[bool]bool{true: true},
+		MapStringString: map[string]string{"k": "v", "a.b[c]": "odd", "": "empty"},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"k": {A: proto.Int32(40)},
+		},
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	m := newPathMessage()
+	tests := []struct {
+		path string
+		want protoreflect.Value
+	}{
+		{"optional_int32", protoreflect.ValueOfInt32(1)},
+		{"optional_nested_message.a", protoreflect.ValueOfInt32(2)},
+		{"optional_nested_message.corecursive.optional_string", protoreflect.ValueOfString("deep")},
+		{"repeated_int32[0]", protoreflect.ValueOfInt32(10)},
+		{"repeated_int32[2]", protoreflect.ValueOfInt32(12)},
+		{"repeated_nested_message[1].a", protoreflect.ValueOfInt32(21)},
+		{"map_int32_int32[-1]", protoreflect.ValueOfInt32(30)},
+		{"map_uint64_uint64[1099511627776]", protoreflect.ValueOfUint64(31)},
+		{"map_bool_bool[true]", protoreflect.ValueOfBool(true)},
+		{`map_string_string["k"]`, protoreflect.ValueOfString("v")},
+		{`map_string_string["a.b[c]"]`, protoreflect.ValueOfString("odd")},
+		{`map_string_string[""]`, protoreflect.ValueOfString("empty")},
+		{`map_string_string["\x6b"]`, protoreflect.ValueOfString("v")},
+		{`map_string_nested_message["k"].a`, protoreflect.ValueOfInt32(40)},
+
+		// Unpopulated fields along the path read as default values.
+		{"optional_int64", protoreflect.ValueOfInt64(0)},
+		{"optional_foreign_message.c", protoreflect.ValueOfInt32(0)},
+		{"optional_nested_message.corecursive.optional_nested_message.a", protoreflect.ValueOfInt32(0)},
+	}
+	for _, tt := range tests {
+		got, err := proto.GetPath(m, tt.path)
+		if err != nil {
+			t.Errorf("GetPath(%q) error: %v", tt.path, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("GetPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// A repeated or map field without a subscript refers to the whole field.
+	if got, err := proto.GetPath(m, "repeated_int32"); err != nil || got.List().Len() != 3 {
+		t.Errorf("GetPath(%q) = %v, %v, want list of length 3", "repeated_int32", got, err)
+	}
+	if got, err := proto.GetPath(m, "map_string_string"); err != nil || got.Map().Len() != 3 {
+		t.Errorf("GetPath(%q) = %v, %v, want map of length 3", "map_string_string", got, err)
+	}
+}
+
+var invalidPaths = []string{
+	"",
+	".",
+	"optional_int32.",
+	".optional_int32",
+	"optional_int32..a",
+	"no_such_field",
+	"optional_nested_message.no_such_field",
+	"OptionalInt32",                     // Go name instead of proto name
+	"optional_int32.a",                  // scalar mid-path
+	"repeated_nested_message.a",         // list mid-path without subscript
+	"map_string_nested_message.a",       // map mid-path without subscript
+	"map_string_string[\"k\"].a",        // scalar map value mid-path
+	"repeated_int32[1].a",               // scalar element mid-path
+	"optional_int32[0]",                 // subscript on a singular field
+	"optional_nested_message[0].a",      // subscript on a singular message field
+	"repeated_int32[]",                  // empty subscript
+	"repeated_int32[0",                  // unterminated subscript
+	"repeated_int32[-1]",                // negative index
+	"repeated_int32[+1]",                // signed index
+	"repeated_int32[ 1]",                // whitespace
+	"repeated_int32[a]",                 // non-numeric index
+	"repeated_int32[1][0]",              // repeated subscript
+	"repeated_int32[0]x",                // junk after subscript
+	"map_int32_int32[1.5]",              // non-integer key
+	"map_int32_int32[4294967296]",       // key out of range
+	"map_uint64_uint64[-1]",             // negative unsigned key
+	"map_bool_bool[1]",                  // non-bool key
+	"map_string_string[k]",              // unquoted string key
+	"map_string_string['k']",            // single-quoted string key
+	"map_string_string[`k`]",            // raw string key
+	`map_string_string["k]`,             // unterminated string key
+	`map_string_string["k"`,             // unterminated subscript
+	`map_string_nested_message["k"]a`,   // junk after subscript
+	`optional_nested_message.a["k"]`,    // subscript on a singular field
+	`map_int32_int32["1"]`,              // quoted integer key
+	`repeated_nested_message["0"].a`,    // quoted index
+	"optional_nested_message a",         // whitespace
+	"goproto.proto.test.optional_int32", // extension name
+}
+
+func TestGetPathErrors(t *testing.T) {
+	m := newPathMessage()
+	paths := append([]string{
+		"repeated_int32[3]",                // index out of range
+		"repeated_int64[0]",                // index into empty list
+		"repeated_nested_message[2].a",     // index out of range mid-path
+		"map_int32_int32[1]",               // key not present
+		`map_string_nested_message["x"].a`, // key not present mid-path
+	}, invalidPaths...)
+	for _, path := range paths {
+		if got, err := proto.GetPath(m, path); err == nil {
+			t.Errorf("GetPath(%q) = %v, want error", path, got)
+		}
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	m := newPathMessage()
+	sets := []struct {
+		path string
+		v    protoreflect.Value
+	}{
+		{"optional_int32", protoreflect.ValueOfInt32(100)},
+		{"optional_nested_message.corecursive.optional_string", protoreflect.ValueOfString("deeper")},
+		{"optional_foreign_message.c", protoreflect.ValueOfInt32(101)},
+		{"repeated_int32[1]", protoreflect.ValueOfInt32(102)},
+		{"repeated_nested_message[0].a", protoreflect.ValueOfInt32(103)},
+		{"repeated_nested_message[1].corecursive.optional_int32", protoreflect.ValueOfInt32(104)},
+		{"map_int32_int32[-1]", protoreflect.ValueOfInt32(105)},
+		{"map_int32_int32[7]", protoreflect.ValueOfInt32(106)},
+		{`map_string_string["new"]`, protoreflect.ValueOfString("107")},
+		{`map_string_nested_message["k"].a`, protoreflect.ValueOfInt32(108)},
+		{`map_string_nested_message["new"].corecursive.optional_int32`, protoreflect.ValueOfInt32(109)},
+	}
+	for _, tt := range sets {
+		if err := proto.SetPath(m, tt.path, tt.v); err != nil {
+			t.Errorf("SetPath(%q) error: %v", tt.path, err)
+			continue
+		}
+		if got, err := proto.GetPath(m, tt.path); err != nil || !got.Equal(tt.v) {
+			t.Errorf("GetPath(%q) after SetPath = %v, %v, want %v", tt.path, got, err, tt.v)
+		}
+	}
+
+	want := newPathMessage()
+	want.OptionalInt32 = proto.Int32(100)
+	want.OptionalNestedMessage.Corecursive.OptionalString = proto.String("deeper")
+	want.OptionalForeignMessage = &testpb.ForeignMessage{C: proto.Int32(101)}
+	want.RepeatedInt32[1] = 102
+	want.RepeatedNestedMessage[0].A = proto.Int32(103)
+	want.RepeatedNestedMessage[1].Corecursive = &testpb.TestAllTypes{OptionalInt32: proto.Int32(104)}
+	want.MapInt32Int32 = map[int32]int32{-1: 105, 7: 106}
+	want.MapStringString["new"] = "107"
+	want.MapStringNestedMessage["k"].A = proto.Int32(108)
+	want.MapStringNestedMessage["new"] = &testpb.TestAllTypes_NestedMessage{
+		Corecursive: &testpb.TestAllTypes{OptionalInt32: proto.Int32(109)},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("SetPath produced %v, want %v", m, want)
+	}
+
+	// A repeated field without a subscript is replaced as a whole.
+	src := &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}}
+	if err := proto.SetPath(m, "repeated_int32", src.ProtoReflect().Get(src.ProtoReflect().Descriptor().Fields().ByName("repeated_int32"))); err != nil {
+		t.Errorf("SetPath(%q) error: %v", "repeated_int32", err)
+	} else if len(m.RepeatedInt32) != 2 {
+		t.Errorf("SetPath(%q) produced %v, want [1 2]", "repeated_int32", m.RepeatedInt32)
+	}
+}
+
+func TestSetPathErrors(t *testing.T) {
+	paths := append([]string{
+		"repeated_int32[3]", // lists are not extended
+		"repeated_int64[0]", // index into empty list
+		"optional_nested_message.corecursive.repeated_int32[0]", // empty list in a new message
+		"repeated_nested_message[2].a",                          // index out of range mid-path
+		`map_string_nested_message["new"].corecursive.repeated_int32[0]`,
+	}, invalidPaths...)
+	for _, path := range paths {
+		m := newPathMessage()
+		m.OptionalNestedMessage = nil
+		if err := proto.SetPath(m, path, protoreflect.ValueOfInt32(1)); err == nil {
+			t.Errorf("SetPath(%q) succeeded, want error", path)
+		}
+		want := newPathMessage()
+		want.OptionalNestedMessage = nil
+		if !proto.Equal(m, want) {
+			t.Errorf("SetPath(%q) modified the message to %v, want %v", path, m, want)
+		}
+	}
+}
+
+func TestGetPathProtopath(t *testing.T) {
+	// protopath.GetByPath shares the path syntax and resolution of GetPath.
+	m := newPathMessage()
+	paths := append([]string{
+		"optional_nested_message.corecursive.optional_string",
+		"repeated_nested_message[1].a",
+		`map_string_nested_message["k"].a`,
+		"repeated_int32[3]",
+	}, invalidPaths...)
+	for _, path := range paths {
+		want, wantErr := proto.GetPath(m, path)
+		got, err := protopath.GetByPath(m.ProtoReflect(), path)
+		if (err != nil) != (wantErr != nil) || !got.Equal(want) {
+			t.Errorf("protopath.GetByPath(%q) = %v, %v, want %v, %v", path, got, err, want, wantErr)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
 )
@@ -56,3 +58,38 @@ func TestReset(t *testing.T) {
 		t.Errorf("m.ProtoReflect().GetUnknown() = %d, want nil", got)
 	}
 }
+
+// TestResetReflective tests resetting a message that does not provide
+// a fast-path Reset method.
+func TestResetReflective(t *testing.T) {
+	b, err := proto.Marshal(&testpb.TestAllTypes{
+		OptionalInt32:          proto.Int32(1),
+		RepeatedString:         []string{"a", "b"},
+		MapInt32Int32:          map[int32]int32{1: 2},
+		OptionalNestedMessage:  &testpb.TestAllTypes_NestedMessage{A: proto.Int32(3)},
+		OneofField:             &testpb.TestAllTypes_OneofUint32{OneofUint32: 4},
+		OptionalForeignMessage: &testpb.ForeignMessage{C: proto.Int32(5)},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	m := dynamicpb.NewMessage((&testpb.TestAllTypes{}).ProtoReflect().Descriptor())
+	if err := proto.Unmarshal(b, m); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	m.SetUnknown(protoreflect.RawFields{0xb8, 0x3e, 0x01}) // field 999, varint 1
+
+	proto.Reset(m)
+
+	n := 0
+	m.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		n++
+		return true
+	})
+	if n != 0 {
+		t.Errorf("after Reset, message has %d populated fields, want 0", n)
+	}
+	if got := len(m.GetUnknown()); got != 0 {
+		t.Errorf("after Reset, len(m.GetUnknown()) = %d, want 0", got)
+	}
+}
@@ -31,6 +31,10 @@ func (o MarshalOptions) Size(m Message) int {
 // introducing other code paths for size that do not go through this.
 func (o MarshalOptions) size(m protoreflect.Message) (size int) {
 	methods := protoMethods(m)
+	if o.DiscardUnknown {
+		// Fast-path implementations have no support for omitting unknown fields.
+		return o.sizeMessageSlow(m)
+	}
 	if methods != nil && methods.Size != nil {
 		out := methods.Size(protoiface.SizeInput{
 			Message: m,
@@ -58,7 +62,9 @@ func (o MarshalOptions) sizeMessageSlow(m protoreflect.Message) (size int) {
 		size += o.sizeField(fd, v)
 		return true
 	})
-	size += len(m.GetUnknown())
+	if !o.DiscardUnknown {
+		size += len(m.GetUnknown())
+	}
 	return size
 }
 
@@ -0,0 +1,36 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protopath
+
+import (
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetByPath retrieves the value addressed by a dot-separated field path
+// relative to m, such as "a.b.c" or `items[2].entries["k"]`.
+// It is equivalent to [proto.GetPath], which documents the path syntax.
+func GetByPath(m protoreflect.Message, path string) (protoreflect.Value, error) {
+	return proto.GetPath(m.Interface(), path)
+}
+
+// RepeatedLen reports the number of elements in the list or map field
+// addressed by path, which uses the same syntax as [GetByPath].
+// It reports an error if path does not address an entire list or map field.
+func RepeatedLen(m protoreflect.Message, path string) (int, error) {
+	v, err := proto.GetPath(m.Interface(), path)
+	if err != nil {
+		return 0, err
+	}
+	switch x := v.Interface().(type) {
+	case protoreflect.List:
+		return x.Len(), nil
+	case protoreflect.Map:
+		return x.Len(), nil
+	default:
+		return 0, errors.New("invalid path %q: not a repeated or map field", path)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protopath_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestGetByPath(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalString: proto.String("deep"),
+			},
+		},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(3)}},
+		MapStringString:       map[string]string{"k": "v"},
+	}
+	mr := m.ProtoReflect()
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{path: "optional_int32", want: int32(1)},
+		{path: "optional_nested_message.a", want: int32(2)},
+		{path: "optional_nested_message.corecursive.optional_string", want: "deep"},
+		{path: "optional_foreign_message.c", want: int32(0)},
+		{path: "repeated_nested_message[0].a", want: int32(3)},
+		{path: `map_string_string["k"]`, want: "v"},
+		{path: "", wantErr: true},
+		{path: "no_such_field", wantErr: true},
+		{path: "optional_nested_message.no_such_field", wantErr: true},
+		{path: "optional_int32.a", wantErr: true},
+		{path: "repeated_nested_message.a", wantErr: true},
+		{path: "repeated_nested_message[1].a", wantErr: true},
+		{path: `map_string_string["x"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := protopath.GetByPath(mr, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetByPath(%q) error = %v, want error? %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Interface() != tt.want {
+			t.Errorf("GetByPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRepeatedLen(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		RepeatedInt32: []int32{1, 2, 3},
+		MapStringString: map[string]string{
+			"a": "b",
+			"c": "d",
+		},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{
+				RepeatedString: []string{"x"},
+			},
+		},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"k": {Corecursive: &testpb.TestAllTypes{RepeatedInt64: []int64{1, 2}}},
+		},
+	}
+	mr := m.ProtoReflect()
+
+	tests := []struct {
+		path    string
+		want    int
+		wantErr bool
+	}{
+		{path: "repeated_int32", want: 3},
+		{path: "map_string_string", want: 2},
+		{path: "repeated_float", want: 0},
+		{path: "optional_nested_message.corecursive.repeated_string", want: 1},
+		{path: `map_string_nested_message["k"].corecursive.repeated_int64`, want: 2},
+		{path: "repeated_int32[0]", wantErr: true},
+		{path: "optional_int32", wantErr: true},
+		{path: "optional_nested_message", wantErr: true},
+		{path: "no_such_field", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := protopath.RepeatedLen(mr, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("RepeatedLen(%q) error = %v, want error? %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RepeatedLen(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
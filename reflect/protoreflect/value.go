@@ -4,7 +4,15 @@
 
 package protoreflect
 
-import "google.golang.org/protobuf/encoding/protowire"
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
 
 // Enum is a reflection interface for a concrete enum value,
 // which provides type information and a getter for the enum number.
@@ -24,6 +32,18 @@ type Enum interface {
 	Number() EnumNumber
 }
 
+// EnumString returns the name of the enum value for e.Number(), as resolved
+// by the enum descriptor of e. If several values share the number
+// (enum aliases), the name of the first one declared is returned.
+// If no value has the number, as may happen with open enums,
+// the number is formatted in decimal.
+func EnumString(e Enum) string {
+	if ev := e.Descriptor().Values().ByNumber(e.Number()); ev != nil {
+		return string(ev.Name())
+	}
+	return strconv.Itoa(int(e.Number()))
+}
+
 // Message is a reflective interface for a concrete message value,
 // encapsulating both type and value information for the message.
 //
@@ -157,12 +177,76 @@ type Message interface {
 	ProtoMethods() *methods
 }
 
+// SetFields sets every field of m whose number is a key in values,
+// applying the updates in ascending order of field number so that setting
+// several members of the same oneof deterministically leaves the member
+// with the largest field number populated.
+// An invalid [Value] clears the corresponding field.
+//
+// It panics without modifying m if any key is not the number of a
+// field declared in the message descriptor. Extension fields are not
+// supported. As with [Message.Set], providing a value of an incorrect
+// type panics, possibly after earlier updates have been applied.
+func SetFields(m Message, values map[FieldNumber]Value) {
+	fields := m.Descriptor().Fields()
+	nums := make([]FieldNumber, 0, len(values))
+	for num := range values {
+		if fields.ByNumber(num) == nil {
+			panic(fmt.Sprintf("invalid field number %d for message %v", num, m.Descriptor().FullName()))
+		}
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		fd := fields.ByNumber(num)
+		if v := values[num]; v.IsValid() {
+			m.Set(fd, v)
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
+
+// RangeFields iterates over every populated field of m, as reported by
+// [Message.Range], for which keep reports true, calling f for each field
+// descriptor and value encountered. It returns immediately if f returns false.
+// While iterating, mutating operations may only be performed
+// on the current field descriptor.
+func RangeFields(m Message, keep func(FieldDescriptor) bool, f func(FieldDescriptor, Value) bool) {
+	m.Range(func(fd FieldDescriptor, v Value) bool {
+		if !keep(fd) {
+			return true
+		}
+		return f(fd, v)
+	})
+}
+
+// RangePresence iterates over every field declared in the message
+// descriptor of m, in declaration order, calling f with the field
+// descriptor, whether the field is populated (see [Message.Has]),
+// and whether the field tracks presence (see [FieldDescriptor.HasPresence]).
+// For a field without presence, populated is false whenever the field holds
+// its default value, so an unpopulated field may still have been set.
+// Extension fields are not visited.
+// It returns immediately if f returns false.
+func RangePresence(m Message, f func(fd FieldDescriptor, populated, hasPresence bool) bool) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !f(fd, m.Has(fd), fd.HasPresence()) {
+			return
+		}
+	}
+}
+
 // RawFields is the raw bytes for an ordered sequence of fields.
 // Each field contains both the tag (representing field number and wire type),
 // and also the wire data itself.
 type RawFields []byte
 
 // IsValid reports whether b is syntactically correct wire format.
+// Every start group tag must be matched by an end group tag
+// with the same field number, with groups properly nested.
 func (b RawFields) IsValid() bool {
 	for len(b) > 0 {
 		_, _, n := protowire.ConsumeField(b)
@@ -174,6 +258,343 @@ func (b RawFields) IsValid() bool {
 	return true
 }
 
+// ListSorted returns the distinct field numbers present in b
+// in ascending order. Parsing stops at the first malformed field.
+func (b RawFields) ListSorted() []FieldNumber {
+	var nums []FieldNumber
+	seen := make(map[FieldNumber]bool)
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			break
+		}
+		if !seen[num] {
+			seen[num] = true
+			nums = append(nums, num)
+		}
+		b = b[n:]
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums
+}
+
+// RangeSorted iterates over the fields in b in ascending order of field number,
+// calling f with each distinct field number and the raw bytes of every field
+// in b with that number, in the order they appear in b.
+// Iteration stops if f returns false.
+// Parsing stops at the first malformed field.
+//
+// Reassembling the RawFields passed to f produces output that does not
+// depend on the order in which the field numbers were originally set.
+func (b RawFields) RangeSorted(f func(FieldNumber, RawFields) bool) {
+	var nums []FieldNumber
+	fields := make(map[FieldNumber]RawFields)
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			break
+		}
+		if _, ok := fields[num]; !ok {
+			nums = append(nums, num)
+		}
+		fields[num] = append(fields[num], b[:n]...)
+		b = b[n:]
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		if !f(num, fields[num]) {
+			return
+		}
+	}
+}
+
+// Sorted returns a copy of b with its fields stably sorted by field number,
+// such that fields with the same number keep their relative order.
+// The bytes of each individual field are left untouched.
+// If b is malformed, it is returned unchanged.
+func (b RawFields) Sorted() RawFields {
+	if !b.IsValid() {
+		return b
+	}
+	out := make(RawFields, 0, len(b))
+	b.RangeSorted(func(_ FieldNumber, raw RawFields) bool {
+		out = append(out, raw...)
+		return true
+	})
+	return out
+}
+
+// AppendValue appends to b the wire encoding of a field with the given number
+// holding v, encoded as the given scalar kind, and returns the extended
+// RawFields. The result remains syntactically valid if b was.
+//
+// The Go type of v must match kind as described for [Value];
+// for example, a [Fixed32Kind] value must be a uint32 and
+// a [Sint64Kind] value must be an int64.
+// It panics if kind is [MessageKind] or [GroupKind],
+// or if v is of an incorrect type.
+func (b RawFields) AppendValue(num FieldNumber, kind Kind, v Value) RawFields {
+	switch kind {
+	case BoolKind:
+		checkAppendValue(v, ValueOfBool(false))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(v.Bool()))
+	case EnumKind:
+		checkAppendValue(v, ValueOfEnum(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v.Enum()))
+	case Int32Kind:
+		checkAppendValue(v, ValueOfInt32(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v.Int()))
+	case Int64Kind:
+		checkAppendValue(v, ValueOfInt64(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v.Int()))
+	case Uint32Kind:
+		checkAppendValue(v, ValueOfUint32(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, v.Uint())
+	case Uint64Kind:
+		checkAppendValue(v, ValueOfUint64(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, v.Uint())
+	case Sint32Kind:
+		checkAppendValue(v, ValueOfInt32(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(v.Int()))
+	case Sint64Kind:
+		checkAppendValue(v, ValueOfInt64(0))
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(v.Int()))
+	case Fixed32Kind:
+		checkAppendValue(v, ValueOfUint32(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, uint32(v.Uint()))
+	case Sfixed32Kind:
+		checkAppendValue(v, ValueOfInt32(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, uint32(v.Int()))
+	case FloatKind:
+		checkAppendValue(v, ValueOfFloat32(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, math.Float32bits(float32(v.Float())))
+	case Fixed64Kind:
+		checkAppendValue(v, ValueOfUint64(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, v.Uint())
+	case Sfixed64Kind:
+		checkAppendValue(v, ValueOfInt64(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, uint64(v.Int()))
+	case DoubleKind:
+		checkAppendValue(v, ValueOfFloat64(0))
+		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(v.Float()))
+	case StringKind:
+		checkAppendValue(v, ValueOfString(""))
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendString(b, v.getString())
+	case BytesKind:
+		checkAppendValue(v, ValueOfBytes(nil))
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, v.getBytes())
+	default:
+		panic(fmt.Sprintf("invalid kind %v for RawFields.AppendValue", kind))
+	}
+	return b
+}
+
+// checkAppendValue panics if v does not have the same Go type as want.
+func checkAppendValue(v, want Value) {
+	if v.typ != want.typ {
+		panic(v.panicMessage(want.typeName()))
+	}
+}
+
+// AppendField appends field, which may hold any number of complete fields,
+// to b and reports true if field is syntactically correct wire format
+// (see [RawFields.IsValid]). Otherwise, it returns b unchanged and false.
+// The result remains syntactically valid if b was.
+func (b RawFields) AppendField(field RawFields) (RawFields, bool) {
+	if !field.IsValid() {
+		return b, false
+	}
+	return append(b, field...), true
+}
+
+// StripField returns a copy of b without any field that has the given number,
+// preserving the order of the remaining fields. b itself is not modified.
+// Parsing stops at the first malformed field, which is kept in the result
+// together with all bytes that follow it.
+func (b RawFields) StripField(num FieldNumber) RawFields {
+	out := make(RawFields, 0, len(b))
+	for len(b) > 0 {
+		fnum, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			break
+		}
+		if fnum != num {
+			out = append(out, b[:n]...)
+		}
+		b = b[n:]
+	}
+	return append(out, b...)
+}
+
+// Dump returns a human-readable rendering of the fields in b for debugging,
+// with one line per field of the form "field=<num> wire=<type> value=<value>".
+// Varint and fixed-width values are printed as unsigned decimal integers.
+// Length-delimited values are printed as their length and a hex prefix of
+// their contents, followed by their fields in braces if the contents are
+// themselves syntactically valid wire format; groups are printed likewise.
+// Dump does not panic on malformed input, but renders the error and the
+// offset at which it occurred in place of the remaining fields.
+// The output is unstable and must not be parsed.
+func (b RawFields) Dump() string {
+	var buf bytes.Buffer
+	dumpRawFields(&buf, b, 0, "", 0)
+	return buf.String()
+}
+
+// dumpRawFields renders the fields in b starting at offset off.
+// If group is non-zero, the fields are the contents of that group and
+// rendering stops after its end-group tag. It reports the offset just past
+// the last field rendered and whether the fields were well-formed.
+//
+// Every byte is parsed exactly once, regardless of nesting depth:
+// groups are rendered while they are being consumed, and length-delimited
+// values are speculatively rendered as sub-messages, with the output
+// discarded if they turn out not to be valid wire format.
+func dumpRawFields(buf *bytes.Buffer, b []byte, off int, indent string, group protowire.Number) (int, bool) {
+	for off < len(b) {
+		num, typ, n := protowire.ConsumeTag(b[off:])
+		if n < 0 {
+			fmt.Fprintf(buf, "%smalformed tag at offset %d: %v\n", indent, off, protowire.ParseError(n))
+			return off, false
+		}
+		if typ == protowire.EndGroupType && num == group && group != 0 {
+			return off + n, true
+		}
+		if typ == protowire.StartGroupType {
+			fmt.Fprintf(buf, "%sfield=%d wire=%s value={\n", indent, num, wireTypeName(typ))
+			end, ok := dumpRawFields(buf, b, off+n, indent+"  ", num)
+			if !ok {
+				return end, false
+			}
+			buf.WriteString(indent + "}\n")
+			off = end
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[off+n:])
+		if m < 0 {
+			fmt.Fprintf(buf, "%sfield=%d wire=%s malformed value at offset %d: %v\n", indent, num, wireTypeName(typ), off+n, protowire.ParseError(m))
+			return off, false
+		}
+		v := b[off+n : off+n+m]
+		fmt.Fprintf(buf, "%sfield=%d wire=%s value=", indent, num, wireTypeName(typ))
+		switch typ {
+		case protowire.VarintType:
+			x, _ := protowire.ConsumeVarint(v)
+			buf.WriteString(strconv.FormatUint(x, 10))
+		case protowire.Fixed32Type:
+			x, _ := protowire.ConsumeFixed32(v)
+			buf.WriteString(strconv.FormatUint(uint64(x), 10))
+		case protowire.Fixed64Type:
+			x, _ := protowire.ConsumeFixed64(v)
+			buf.WriteString(strconv.FormatUint(x, 10))
+		case protowire.BytesType:
+			x, _ := protowire.ConsumeBytes(v)
+			const maxHex = 16
+			if len(x) > maxHex {
+				fmt.Fprintf(buf, "len=%d %x...", len(x), x[:maxHex])
+			} else {
+				fmt.Fprintf(buf, "len=%d %x", len(x), x)
+			}
+			if len(x) > 0 {
+				mark := buf.Len()
+				buf.WriteString(" {\n")
+				if _, ok := dumpRawFields(buf, x, 0, indent+"  ", 0); ok {
+					buf.WriteString(indent + "}")
+				} else {
+					buf.Truncate(mark)
+				}
+			}
+		}
+		buf.WriteByte('\n')
+		off += n + m
+	}
+	if group != 0 {
+		// The input ended before the end-group tag.
+		_, _, n := protowire.ConsumeTag(b[off:])
+		fmt.Fprintf(buf, "%smalformed tag at offset %d: %v\n", indent, off, protowire.ParseError(n))
+		return off, false
+	}
+	return off, true
+}
+
+// wireTypeName returns the name of a wire type as used by [RawFields.Dump].
+func wireTypeName(typ protowire.Type) string {
+	switch typ {
+	case protowire.VarintType:
+		return "varint"
+	case protowire.Fixed32Type:
+		return "fixed32"
+	case protowire.Fixed64Type:
+		return "fixed64"
+	case protowire.BytesType:
+		return "bytes"
+	case protowire.StartGroupType:
+		return "group"
+	case protowire.EndGroupType:
+		return "endgroup"
+	default:
+		return fmt.Sprintf("unknown(%d)", typ)
+	}
+}
+
+// Count returns the number of fields in b, counting every occurrence of a
+// repeated field number separately. It returns -1 if b is malformed.
+func (b RawFields) Count() int {
+	var count int
+	for len(b) > 0 {
+		_, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			return -1
+		}
+		b = b[n:]
+		count++
+	}
+	return count
+}
+
+// SubMessage returns the contents, without the length prefix, of the first
+// field in b with the given number, reporting false if there is no such field
+// or if that field is not length-delimited.
+// Later fields with the same number are ignored.
+// Parsing stops at the first malformed field.
+func (b RawFields) SubMessage(num FieldNumber) (RawFields, bool) {
+	for len(b) > 0 {
+		fnum, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, false
+		}
+		m := protowire.ConsumeFieldValue(fnum, typ, b[n:])
+		if m < 0 {
+			return nil, false
+		}
+		if fnum == num {
+			if typ != protowire.BytesType {
+				return nil, false
+			}
+			v, _ := protowire.ConsumeBytes(b[n:])
+			return RawFields(v), true
+		}
+		b = b[n+m:]
+	}
+	return nil, false
+}
+
 // List is a zero-indexed, ordered list.
 // The element [Value] type is determined by [FieldDescriptor.Kind].
 // Providing a [Value] that is invalid or of an incorrect type panics.
@@ -225,6 +646,260 @@ type List interface {
 	IsValid() bool
 }
 
+// FilterList removes from l, in place, every element for which keep
+// reports false, preserving the relative order of the remaining elements.
+// The keep function is called once per element, in order,
+// with the element's original index.
+//
+// Removed elements are discarded with [List.Truncate], so whether the
+// memory of removed composite elements is retained depends on the
+// implementation of l; lists of generated messages may keep it alive
+// until the list is next grown or reset.
+func FilterList(l List, keep func(i int, v Value) bool) {
+	n := 0
+	for i := 0; i < l.Len(); i++ {
+		v := l.Get(i)
+		if !keep(i, v) {
+			continue
+		}
+		if n != i {
+			l.Set(n, v)
+		}
+		n++
+	}
+	l.Truncate(n)
+}
+
+// SubList returns a view of the elements of l in the range [low, high),
+// where index 0 of the view corresponds to index low of l.
+// Like a Go slice expression, the view shares elements with l:
+// Get and Set on the view read from and write to l.
+// Truncate shortens only the view and leaves l unchanged, while
+// Append and AppendMutable are unsupported and panic.
+// The view does not track later changes to the length of l.
+//
+// It panics unless 0 <= low <= high <= l.Len().
+func SubList(l List, low, high int) List {
+	if low < 0 || high < low || high > l.Len() {
+		panic(fmt.Sprintf("invalid SubList bounds [%d:%d] for list of length %d", low, high, l.Len()))
+	}
+	return &subList{list: l, low: low, high: high}
+}
+
+// ListRange returns a snapshot of the elements of l in the range [low, high)
+// as a newly allocated slice. Unlike [SubList], the result does not share
+// storage with l: scalar elements are copied, including the contents of
+// bytes values, so modifying the result does not affect l.
+// Message elements are not copied and still refer to the messages held by l.
+//
+// It panics unless 0 <= low <= high <= l.Len().
+func ListRange(l List, low, high int) []Value {
+	if low < 0 || high < low || high > l.Len() {
+		panic(fmt.Sprintf("invalid ListRange bounds [%d:%d] for list of length %d", low, high, l.Len()))
+	}
+	vs := make([]Value, high-low)
+	for i := range vs {
+		v := l.Get(low + i)
+		if v.typ == bytesType {
+			v = ValueOfBytes(append([]byte(nil), v.getBytes()...))
+		}
+		vs[i] = v
+	}
+	return vs
+}
+
+// AppendInt64s appends every element of vs to l,
+// which must be a list of int64, sint64, or sfixed64 values.
+// It panics if l has any other element type.
+func AppendInt64s(l List, vs []int64) {
+	checkListElement(l, ValueOfInt64(0), "AppendInt64s")
+	for _, v := range vs {
+		l.Append(ValueOfInt64(v))
+	}
+}
+
+// AppendStrings appends every element of vs to l,
+// which must be a list of string values.
+// It panics if l has any other element type.
+func AppendStrings(l List, vs []string) {
+	checkListElement(l, ValueOfString(""), "AppendStrings")
+	for _, v := range vs {
+		l.Append(ValueOfString(v))
+	}
+}
+
+// AppendBytesList appends every element of vs to l,
+// which must be a list of bytes values.
+// The byte slices are not copied, so l may alias the memory of vs.
+// It panics if l has any other element type.
+func AppendBytesList(l List, vs [][]byte) {
+	checkListElement(l, ValueOfBytes(nil), "AppendBytesList")
+	for _, v := range vs {
+		l.Append(ValueOfBytes(v))
+	}
+}
+
+// SetInt64s replaces the contents of l with the elements of vs.
+// It panics without modifying l if l is not a list of
+// int64, sint64, or sfixed64 values.
+func SetInt64s(l List, vs []int64) {
+	checkListElement(l, ValueOfInt64(0), "SetInt64s")
+	l.Truncate(0)
+	AppendInt64s(l, vs)
+}
+
+// SetStrings replaces the contents of l with the elements of vs.
+// It panics without modifying l if l is not a list of string values.
+func SetStrings(l List, vs []string) {
+	checkListElement(l, ValueOfString(""), "SetStrings")
+	l.Truncate(0)
+	AppendStrings(l, vs)
+}
+
+// SetBytesList replaces the contents of l with the elements of vs.
+// The byte slices are not copied, so l may alias the memory of vs.
+// It panics without modifying l if l is not a list of bytes values.
+func SetBytesList(l List, vs [][]byte) {
+	checkListElement(l, ValueOfBytes(nil), "SetBytesList")
+	l.Truncate(0)
+	AppendBytesList(l, vs)
+}
+
+// Int64s returns a copy of the elements of l,
+// which must be a list of int64, sint64, or sfixed64 values.
+// Later changes to l are not reflected in the returned slice.
+// It panics if l has any other element type.
+//
+// Lists of generated messages are copied directly from the Go slice
+// that backs them; other lists are read element by element with Get.
+func Int64s(l List) []int64 {
+	checkListElement(l, ValueOfInt64(0), "Int64s")
+	if a, ok := l.(int64Lister); ok {
+		if vs, ok := a.AppendInt64s(make([]int64, 0, l.Len())); ok {
+			return vs
+		}
+	}
+	vs := make([]int64, l.Len())
+	for i := range vs {
+		vs[i] = int64(l.Get(i).num)
+	}
+	return vs
+}
+
+// Int64At returns the element at index i of l,
+// which must be a list of int64, sint64, or sfixed64 values.
+// Unlike [Int64s], it reads l directly without copying the other elements.
+// It panics if l has any other element type or if i is out of range.
+//
+// Lists of generated messages are read directly from the Go slice
+// that backs them without constructing a [Value] for the element.
+func Int64At(l List, i int) int64 {
+	if a, ok := l.(int64Lister); ok {
+		if x, ok := a.Int64At(i); ok {
+			return x
+		}
+	}
+	v := l.Get(i)
+	if v.typ != int64Type {
+		panic(fmt.Sprintf("invalid Int64At on list of %v elements", elementTypeName(v)))
+	}
+	return int64(v.num)
+}
+
+// int64Lister is optionally implemented by a [List] that can read
+// its int64 elements without constructing a [Value] for each one.
+// Both methods report false if the list does not hold int64 values.
+type int64Lister interface {
+	Int64At(i int) (int64, bool)
+	AppendInt64s(b []int64) ([]int64, bool)
+}
+
+// checkListElement panics if the elements of l do not have the same type as want.
+func checkListElement(l List, want Value, name string) {
+	if e := l.NewElement(); e.typ != want.typ {
+		panic(fmt.Sprintf("invalid %s on list of %v elements", name, elementTypeName(e)))
+	}
+}
+
+// ReverseList reverses the order of the elements of l in place.
+// Elements are swapped with [List.Get] and [List.Set],
+// so bytes and message elements are moved without being copied.
+func ReverseList(l List) {
+	for i, j := 0, l.Len()-1; i < j; i, j = i+1, j-1 {
+		vi, vj := l.Get(i), l.Get(j)
+		l.Set(i, vj)
+		l.Set(j, vi)
+	}
+}
+
+// IsPackable reports whether the elements of l may use the packed
+// wire encoding, which is the case for lists of bool, enum, integer,
+// and floating-point values, but not for lists of strings, bytes, or messages.
+// It is determined from the type of [List.NewElement]; whether a field is
+// actually encoded packed is reported by [FieldDescriptor.IsPacked].
+func IsPackable(l List) bool {
+	switch l.NewElement().typ {
+	case boolType, enumType, int32Type, int64Type, uint32Type, uint64Type, float32Type, float64Type:
+		return true
+	default:
+		return false
+	}
+}
+
+// GrowList hints that l should reserve capacity for at least n more
+// [List.Append] or [List.AppendMutable] calls, reducing reallocation
+// when the final length is known in advance.
+// It never changes the contents or length of l, only its capacity.
+// The hint has no effect unless the implementation of l provides
+// a Grow(int) method; the list implementations in this module do,
+// except for read-only lists.
+func GrowList(l List, n int) {
+	if g, ok := l.(interface{ Grow(int) }); ok && n > 0 {
+		g.Grow(n)
+	}
+}
+
+// ListIndex returns the index of the first element of l that is equal to v
+// according to [Value.Equal], or -1 if there is no such element.
+// Message elements are compared deeply.
+func ListIndex(l List, v Value) int {
+	for i := 0; i < l.Len(); i++ {
+		if l.Get(i).Equal(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+type subList struct {
+	list      List
+	low, high int
+}
+
+func (x *subList) Len() int           { return x.high - x.low }
+func (x *subList) Get(i int) Value    { return x.list.Get(x.index(i)) }
+func (x *subList) Set(i int, v Value) { x.list.Set(x.index(i), v) }
+func (x *subList) Append(Value)       { panic("invalid Append on SubList view") }
+func (x *subList) AppendMutable() Value {
+	panic("invalid AppendMutable on SubList view")
+}
+func (x *subList) Truncate(n int) {
+	if n < 0 || n > x.Len() {
+		panic(fmt.Sprintf("invalid Truncate length %d for SubList view of length %d", n, x.Len()))
+	}
+	x.high = x.low + n
+}
+func (x *subList) NewElement() Value { return x.list.NewElement() }
+func (x *subList) IsValid() bool     { return x.list.IsValid() }
+
+// index returns the index in the parent list of element i of the view.
+func (x *subList) index(i int) int {
+	if i < 0 || i >= x.Len() {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, x.Len()))
+	}
+	return x.low + i
+}
+
 // Map is an unordered, associative map.
 // The entry [MapKey] type is determined by [FieldDescriptor.MapKey].Kind.
 // The entry [Value] type is determined by [FieldDescriptor.MapValue].Kind.
@@ -283,3 +958,101 @@ type Map interface {
 	// be preserved in marshaling or other operations.
 	IsValid() bool
 }
+
+// MapKeys returns the keys of m in ascending order as defined by [MapKey.Less].
+// This is the same ordering used for deterministic map iteration elsewhere
+// in this module. Use [Map.Range] if the order of keys does not matter.
+func MapKeys(m Map) []MapKey {
+	keys := make([]MapKey, 0, m.Len())
+	m.Range(func(k MapKey, _ Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Less(keys[j]) })
+	return keys
+}
+
+// RangeStringKeys iterates over every entry of m in an undefined order,
+// calling f with each key as a Go string. Iteration stops if f returns false.
+// It panics if the keys of m are not strings, even if m is empty.
+func RangeStringKeys(m Map, f func(string, Value) bool) {
+	checkMapKey(m, ValueOfString(""), "RangeStringKeys")
+	m.Range(func(k MapKey, v Value) bool {
+		return f(k.String(), v)
+	})
+}
+
+// RangeInt64Keys iterates over every entry of m in an undefined order,
+// calling f with each key as a Go int64. Iteration stops if f returns false.
+// It panics if the keys of m are not int64, sint64, or sfixed64 values,
+// even if m is empty.
+func RangeInt64Keys(m Map, f func(int64, Value) bool) {
+	checkMapKey(m, ValueOfInt64(0), "RangeInt64Keys")
+	m.Range(func(k MapKey, v Value) bool {
+		return f(k.Int(), v)
+	})
+}
+
+// checkMapKey panics if the keys of m do not have the same type as want.
+// The key type of an empty map can only be checked if its implementation
+// provides a NewKey method, as the map implementations in this module do.
+func checkMapKey(m Map, want Value, name string) {
+	if k := mapKeyType(m); k.IsValid() && k.typ != want.typ {
+		panic(fmt.Sprintf("invalid %s on map with %v keys", name, Value(k).typeName()))
+	}
+}
+
+// GrowMap hints that m should reserve space for at least n more entries,
+// reducing the cost of a following series of [Map.Set] calls.
+// It never changes the contents or length of m.
+// The hint has no effect unless the implementation of m provides
+// a Grow(int) method; the map implementations in this module do,
+// except for read-only maps.
+//
+// Since a Go map cannot be grown in place, the map implementations in this
+// module only act on the hint if m is empty, so that GrowMap never copies
+// existing entries. It is meant to be called once, before m is populated.
+func GrowMap(m Map, n int) {
+	if g, ok := m.(interface{ Grow(int) }); ok && n > 0 {
+		g.Grow(n)
+	}
+}
+
+// MapGetOrDefault returns the value stored in m for key k,
+// or def if m has no entry for k.
+// The type of def is not checked against the map value type.
+func MapGetOrDefault(m Map, k MapKey, def Value) Value {
+	if !m.Has(k) {
+		return def
+	}
+	return m.Get(k)
+}
+
+// MapGetInt64 returns the value stored in m for key k and reports whether
+// m has an entry for k. It panics if the values of m are not
+// int64, sint64, or sfixed64 values, even if there is no entry for k.
+func MapGetInt64(m Map, k MapKey) (int64, bool) {
+	checkMapValue(m, ValueOfInt64(0), "MapGetInt64")
+	if !m.Has(k) {
+		return 0, false
+	}
+	return m.Get(k).Int(), true
+}
+
+// MapGetString returns the value stored in m for key k and reports whether
+// m has an entry for k. It panics if the values of m are not string values,
+// even if there is no entry for k.
+func MapGetString(m Map, k MapKey) (string, bool) {
+	checkMapValue(m, ValueOfString(""), "MapGetString")
+	if !m.Has(k) {
+		return "", false
+	}
+	return m.Get(k).String(), true
+}
+
+// checkMapValue panics if the values of m do not have the same type as want.
+func checkMapValue(m Map, want Value, name string) {
+	if v := m.NewValue(); v.typ != want.typ {
+		panic(fmt.Sprintf("invalid %s on map of %v values", name, elementTypeName(v)))
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect
+
+import (
+	"fmt"
+)
+
+// Clone returns a deep copy of v.
+//
+//   - An invalid value is returned as is.
+//
+//   - Scalar values are returned by value, except that bytes values
+//     are copied into a newly allocated slice.
+//
+//   - [Message] values are copied by creating a new message of the same type
+//     and recursively cloning every populated field, including unknown fields.
+//     An invalid message is returned as is.
+//
+//   - [List] and [Map] values are copied into a new, standalone List or Map
+//     that is not owned by any message. Their elements are recursively cloned.
+//     The standalone List or Map can be read from, modified, and
+//     ranged over, but it cannot be passed to [Message.Set] on a message
+//     whose implementation requires its own list or map types;
+//     use [Value.CloneInto] to copy a list or map into a message field.
+func (v Value) Clone() Value {
+	switch v.typ {
+	case bytesType:
+		return ValueOfBytes(append([]byte(nil), v.getBytes()...))
+	case nilType, boolType, int32Type, int64Type, uint32Type, uint64Type, float32Type, float64Type, stringType, enumType:
+		return v
+	default:
+		switch x := v.getIface().(type) {
+		case Message:
+			return ValueOfMessage(cloneMessage(x))
+		case List:
+			return ValueOfList(cloneList(x))
+		case Map:
+			return ValueOfMap(cloneMap(x))
+		}
+	}
+	return v
+}
+
+// CloneInto sets the field fd of dst to a deep copy of v,
+// which must be a value of that field, such as one returned by
+// [Message.Get] on another message with the same field.
+// Unlike [Value.Clone], the copy of a [List], [Map], or [Message]
+// is created by dst with [Message.NewField], so that it can be stored in dst
+// regardless of which message implementation v came from.
+// Elements, entries, and fields are cloned recursively.
+// An invalid message or an empty list or map clears the field.
+//
+// It panics under the same conditions as [Message.Set].
+func (v Value) CloneInto(dst Message, fd FieldDescriptor) {
+	switch {
+	case fd.IsList():
+		if v.List().Len() == 0 {
+			dst.Clear(fd)
+			return
+		}
+		l := dst.NewField(fd).List()
+		CopyList(l, v.List())
+		dst.Set(fd, ValueOfList(l))
+	case fd.IsMap():
+		if v.Map().Len() == 0 {
+			dst.Clear(fd)
+			return
+		}
+		m := dst.NewField(fd).Map()
+		CopyMap(m, v.Map())
+		dst.Set(fd, ValueOfMap(m))
+	case fd.Message() != nil:
+		if !v.Message().IsValid() {
+			dst.Clear(fd)
+			return
+		}
+		m := dst.NewField(fd).Message()
+		copyMessage(m, v.Message())
+		dst.Set(fd, ValueOfMessage(m))
+	default:
+		dst.Set(fd, v.Clone())
+	}
+}
+
+func cloneMessage(src Message) Message {
+	if !src.IsValid() {
+		return src
+	}
+	dst := src.New()
+	copyMessage(dst, src)
+	return dst
+}
+
+// copyMessage copies every populated field of src into dst,
+// which must be a mutable message of the same message descriptor.
+func copyMessage(dst, src Message) {
+	src.Range(func(fd FieldDescriptor, v Value) bool {
+		switch {
+		case fd.IsList():
+			CopyList(dst.Mutable(fd).List(), v.List())
+		case fd.IsMap():
+			CopyMap(dst.Mutable(fd).Map(), v.Map())
+		case fd.Message() != nil:
+			copyMessage(dst.Mutable(fd).Message(), v.Message())
+		default:
+			dst.Set(fd, v.Clone())
+		}
+		return true
+	})
+	if u := src.GetUnknown(); len(u) > 0 {
+		dst.SetUnknown(append(dst.GetUnknown(), u...))
+	}
+}
+
+// CopyList appends a deep copy of every element of src to dst.
+// Bytes and message elements are cloned so that dst does not alias src;
+// message elements are copied into new elements created by dst.
+//
+// It panics if the element types of dst and src differ.
+func CopyList(dst, src List) {
+	if dt, st := elementTypeName(dst.NewElement()), elementTypeName(src.NewElement()); dt != st {
+		panic(fmt.Sprintf("invalid CopyList: mismatching element types %v and %v", dt, st))
+	}
+	for i := 0; i < src.Len(); i++ {
+		v := src.Get(i)
+		if v.IsMessage() {
+			e := dst.NewElement()
+			copyMessage(e.Message(), v.Message())
+			v = e
+		} else {
+			v = v.Clone()
+		}
+		dst.Append(v)
+	}
+}
+
+// CopyMap copies every entry of src into dst, replacing the value of any
+// key already present in dst. Bytes and message values are cloned so that
+// dst does not alias src; message values are copied into new values
+// created by dst.
+//
+// It panics if the key or value types of dst and src differ,
+// even if either map is empty. The key types of maps implemented outside
+// this module are only compared if both maps are non-empty.
+func CopyMap(dst, src Map) {
+	checkMapTypes("CopyMap", dst, src)
+	src.Range(func(k MapKey, v Value) bool {
+		dst.Set(k, cloneMapValue(dst, v))
+		return true
+	})
+}
+
+// MergeMapFunc copies every entry of src into dst. For a key already
+// present in dst, it instead stores the value returned by resolve,
+// which is called with the key, the value in dst, and the value in src.
+// Values copied from src are cloned as by [CopyMap], while the value
+// returned by resolve is stored as is and may alias either map.
+//
+// It panics under the same conditions as [CopyMap],
+// before calling resolve or modifying dst.
+func MergeMapFunc(dst, src Map, resolve func(k MapKey, dst, src Value) Value) {
+	checkMapTypes("MergeMapFunc", dst, src)
+	src.Range(func(k MapKey, v Value) bool {
+		if dst.Has(k) {
+			dst.Set(k, resolve(k, dst.Get(k), v))
+		} else {
+			dst.Set(k, cloneMapValue(dst, v))
+		}
+		return true
+	})
+}
+
+// cloneMapValue returns a deep copy of v, a value of a map of the same type
+// as dst. Message values are copied into a new value created by dst.
+func cloneMapValue(dst Map, v Value) Value {
+	if !v.IsMessage() {
+		return v.Clone()
+	}
+	e := dst.NewValue()
+	copyMessage(e.Message(), v.Message())
+	return e
+}
+
+// checkMapTypes panics if the key or value types of dst and src differ.
+func checkMapTypes(name string, dst, src Map) {
+	if dt, st := elementTypeName(dst.NewValue()), elementTypeName(src.NewValue()); dt != st {
+		panic(fmt.Sprintf("invalid %s: mismatching value types %v and %v", name, dt, st))
+	}
+	if dk, sk := mapKeyType(dst), mapKeyType(src); dk.IsValid() && sk.IsValid() && dk.typ != sk.typ {
+		panic(fmt.Sprintf("invalid %s: mismatching key types %v and %v", name, Value(dk).typeName(), Value(sk).typeName()))
+	}
+}
+
+// mapKeyType returns a key of the same type as the keys of m.
+// It is the zero key if the implementation of m provides a NewKey method,
+// as the map implementations in this module do, and otherwise any key of m.
+// It is invalid if m is empty and does not provide a NewKey method.
+func mapKeyType(m Map) (k MapKey) {
+	if m, ok := m.(interface{ NewKey() MapKey }); ok {
+		return m.NewKey()
+	}
+	m.Range(func(mk MapKey, _ Value) bool {
+		k = mk
+		return false
+	})
+	return k
+}
+
+// elementTypeName returns a name for the type of a list element or map value,
+// distinguishing messages by their full name.
+func elementTypeName(v Value) string {
+	if v.IsMessage() {
+		return string(v.Message().Descriptor().FullName())
+	}
+	return v.typeName()
+}
+
+func cloneList(src List) List {
+	dst := &clonedList{newElement: src.NewElement}
+	for i := 0; i < src.Len(); i++ {
+		dst.list = append(dst.list, src.Get(i).Clone())
+	}
+	return dst
+}
+
+func cloneMap(src Map) Map {
+	dst := &clonedMap{newValue: src.NewValue, m: make(map[interface{}]Value, src.Len())}
+	src.Range(func(k MapKey, v Value) bool {
+		dst.m[k.Interface()] = v.Clone()
+		return true
+	})
+	return dst
+}
+
+// clonedList is a standalone List produced by [Value.Clone].
+type clonedList struct {
+	newElement func() Value
+	list       []Value
+}
+
+func (x *clonedList) Len() int           { return len(x.list) }
+func (x *clonedList) Get(i int) Value    { return x.list[i] }
+func (x *clonedList) Set(i int, v Value) { x.list[i] = v }
+func (x *clonedList) Append(v Value)     { x.list = append(x.list, v) }
+func (x *clonedList) Truncate(n int) {
+	// Zero truncated elements to avoid keeping data live.
+	for i := n; i < len(x.list); i++ {
+		x.list[i] = Value{}
+	}
+	x.list = x.list[:n]
+}
+func (x *clonedList) NewElement() Value { return x.newElement() }
+func (x *clonedList) IsValid() bool     { return true }
+func (x *clonedList) Grow(n int) {
+	if cap(x.list)-len(x.list) < n {
+		x.list = append(make([]Value, 0, len(x.list)+n), x.list...)
+	}
+}
+func (x *clonedList) AppendMutable() Value {
+	v := x.NewElement()
+	if _, ok := v.Interface().(Message); !ok {
+		panic("invalid AppendMutable on list with non-message type")
+	}
+	x.Append(v)
+	return v
+}
+
+// clonedMap is a standalone Map produced by [Value.Clone].
+type clonedMap struct {
+	newValue func() Value
+	m        map[interface{}]Value
+}
+
+func (x *clonedMap) Len() int              { return len(x.m) }
+func (x *clonedMap) Has(k MapKey) bool     { _, ok := x.m[k.Interface()]; return ok }
+func (x *clonedMap) Get(k MapKey) Value    { return x.m[k.Interface()] }
+func (x *clonedMap) Set(k MapKey, v Value) { x.m[k.Interface()] = v }
+func (x *clonedMap) Clear(k MapKey)        { delete(x.m, k.Interface()) }
+func (x *clonedMap) NewValue() Value       { return x.newValue() }
+func (x *clonedMap) IsValid() bool         { return true }
+func (x *clonedMap) Grow(n int) {
+	if len(x.m) == 0 {
+		x.m = make(map[interface{}]Value, n)
+	}
+}
+func (x *clonedMap) Mutable(k MapKey) Value {
+	if v, ok := x.m[k.Interface()]; ok {
+		return v
+	}
+	v := x.NewValue()
+	if _, ok := v.Interface().(Message); !ok {
+		panic("invalid Mutable on map with non-message value type")
+	}
+	x.m[k.Interface()] = v
+	return v
+}
+func (x *clonedMap) Range(f func(MapKey, Value) bool) {
+	for k, v := range x.m {
+		if !f(ValueOf(k).MapKey(), v) {
+			return
+		}
+	}
+}
@@ -0,0 +1,476 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	legacypb "google.golang.org/protobuf/internal/testprotos/legacy/proto3_20180430_b4deda09"
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+// mustPanic reports an error if f does not panic.
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%v: did not panic", name)
+		}
+	}()
+	f()
+}
+
+func TestValueCloneScalar(t *testing.T) {
+	for _, v := range []protoreflect.Value{
+		{},
+		protoreflect.ValueOfBool(true),
+		protoreflect.ValueOfInt32(-32),
+		protoreflect.ValueOfUint64(64),
+		protoreflect.ValueOfFloat64(1.5),
+		protoreflect.ValueOfString("hello"),
+		protoreflect.ValueOfEnum(5),
+		protoreflect.ValueOfBytes(nil),
+	} {
+		if got := v.Clone(); !got.Equal(v) {
+			t.Errorf("Value(%v).Clone() = %v, want equal value", v, got)
+		}
+	}
+
+	b := []byte("hello")
+	v := protoreflect.ValueOfBytes(b)
+	c := v.Clone()
+	c.Bytes()[0] = 'j'
+	if string(b) != "hello" || string(v.Bytes()) != "hello" {
+		t.Errorf("mutating cloned bytes changed the source to %q", b)
+	}
+	if string(c.Bytes()) != "jello" {
+		t.Errorf("cloned bytes = %q, want %q", c.Bytes(), "jello")
+	}
+}
+
+func TestValueCloneMessage(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalBytes:         []byte("bytes"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(3)}},
+		RepeatedBytes:         [][]byte{[]byte("a")},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"k": {A: proto.Int32(4)},
+		},
+	}
+	src.ProtoReflect().SetUnknown(protoreflect.RawFields{0xb8, 0x3e, 0x01})
+	want := proto.Clone(src)
+
+	v := protoreflect.ValueOfMessage(src.ProtoReflect()).Clone()
+	got := v.Message().Interface().(*testpb.TestAllTypes)
+	if !proto.Equal(got, src) {
+		t.Fatalf("Clone() = %v, want %v", got, src)
+	}
+
+	got.OptionalBytes[0] = 'B'
+	got.OptionalNestedMessage.A = proto.Int32(20)
+	got.RepeatedNestedMessage[0].A = proto.Int32(30)
+	got.RepeatedBytes[0][0] = 'A'
+	got.MapStringNestedMessage["k"].A = proto.Int32(40)
+	got.ProtoReflect().GetUnknown()[2] = 0x02
+	if !proto.Equal(src, want) {
+		t.Errorf("mutating the clone changed the source to %v, want %v", src, want)
+	}
+
+	var nilMsg *testpb.TestAllTypes
+	if got := protoreflect.ValueOfMessage(nilMsg.ProtoReflect()).Clone(); got.Message().IsValid() {
+		t.Errorf("Clone() of an invalid message = %v, want invalid message", got)
+	}
+}
+
+func TestValueCloneList(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(1)},
+			{Corecursive: &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}}},
+		},
+		RepeatedBytes: [][]byte{[]byte("a")},
+	}
+	want := proto.Clone(src)
+	fields := src.ProtoReflect().Descriptor().Fields()
+	fd := fields.ByName("repeated_nested_message")
+
+	for _, dst := range []protoreflect.Message{
+		(&testpb.TestAllTypes{}).ProtoReflect(),
+		dynamicpb.NewMessage(src.ProtoReflect().Descriptor()),
+	} {
+		for _, name := range []protoreflect.Name{"repeated_nested_message", "repeated_bytes"} {
+			fd := fields.ByName(name)
+			src.ProtoReflect().Get(fd).CloneInto(dst, fd)
+			if got, want := dst.Get(fd), src.ProtoReflect().Get(fd); !got.Equal(want) {
+				t.Fatalf("%T: CloneInto(%v) = %v, want %v", dst, name, got, want)
+			}
+		}
+		l := dst.Mutable(fd).List()
+		l.Get(0).Message().Set(fd.Message().Fields().ByName("a"), protoreflect.ValueOfInt32(10))
+		l.Get(1).Message().Mutable(fd.Message().Fields().ByName("corecursive")).Message().Mutable(fields.ByName("repeated_int32")).List().Set(0, protoreflect.ValueOfInt32(10))
+		l.AppendMutable()
+		copy(dst.Get(fields.ByName("repeated_bytes")).List().Get(0).Bytes(), "b")
+		if !proto.Equal(src, want) {
+			t.Errorf("%T: mutating the cloned lists changed the source to %v, want %v", dst, src, want)
+		}
+		if l.Len() != 3 || len(src.RepeatedNestedMessage) != 2 {
+			t.Errorf("%T: cloned list length = %d, source list length = %d, want 3 and 2", dst, l.Len(), len(src.RepeatedNestedMessage))
+		}
+
+		// Cloning an empty list clears the field.
+		(&testpb.TestAllTypes{}).ProtoReflect().Get(fd).CloneInto(dst, fd)
+		if dst.Has(fd) {
+			t.Errorf("%T: CloneInto of an empty list left the field populated", dst)
+		}
+	}
+
+	// Clone returns a standalone list whose nested elements are copies.
+	v := src.ProtoReflect().Get(fd)
+	c := v.Clone()
+	if !c.Equal(v) {
+		t.Fatalf("Clone() = %v, want %v", c, v)
+	}
+	l := c.List()
+	l.Get(0).Message().Interface().(*testpb.TestAllTypes_NestedMessage).A = proto.Int32(10)
+	l.Get(1).Message().Interface().(*testpb.TestAllTypes_NestedMessage).Corecursive.RepeatedInt32[0] = 10
+	l.AppendMutable().Message().Set(fd.Message().Fields().ByName("a"), protoreflect.ValueOfInt32(3))
+	l.Append(l.NewElement())
+	bl := src.ProtoReflect().Get(fields.ByName("repeated_bytes")).Clone().List()
+	copy(bl.Get(0).Bytes(), "b")
+	if !proto.Equal(src, want) {
+		t.Errorf("mutating the cloned list changed the source to %v, want %v", src, want)
+	}
+	if l.Len() != 4 || len(src.RepeatedNestedMessage) != 2 {
+		t.Errorf("cloned list length = %d, source list length = %d, want 4 and 2", l.Len(), len(src.RepeatedNestedMessage))
+	}
+
+	// A standalone list can be stored in a message with CloneInto.
+	dst := &testpb.TestAllTypes{}
+	c.CloneInto(dst.ProtoReflect(), fd)
+	if got := len(dst.RepeatedNestedMessage); got != 4 {
+		t.Errorf("CloneInto of a cloned list stored %d elements, want 4", got)
+	}
+}
+
+func TestValueCloneMap(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		MapInt32Int32: map[int32]int32{1: 2, 3: 4},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"a": {A: proto.Int32(1)},
+		},
+	}
+	want := proto.Clone(src)
+	fields := src.ProtoReflect().Descriptor().Fields()
+
+	for _, dst := range []protoreflect.Message{
+		(&testpb.TestAllTypes{}).ProtoReflect(),
+		dynamicpb.NewMessage(src.ProtoReflect().Descriptor()),
+	} {
+		for _, name := range []protoreflect.Name{"map_int32_int32", "map_string_nested_message"} {
+			fd := fields.ByName(name)
+			src.ProtoReflect().Get(fd).CloneInto(dst, fd)
+			if got, want := dst.Get(fd), src.ProtoReflect().Get(fd); !got.Equal(want) {
+				t.Fatalf("%T: CloneInto(%v) = %v, want %v", dst, name, got, want)
+			}
+		}
+		m := dst.Mutable(fields.ByName("map_int32_int32")).Map()
+		m.Set(protoreflect.ValueOfInt32(1).MapKey(), protoreflect.ValueOfInt32(20))
+		m.Clear(protoreflect.ValueOfInt32(3).MapKey())
+		if m.Len() != 1 || m.Get(protoreflect.ValueOfInt32(1).MapKey()).Int() != 20 {
+			t.Errorf("%T: cloned map after mutation = %v", dst, m)
+		}
+		fd := fields.ByName("map_string_nested_message")
+		m = dst.Mutable(fd).Map()
+		m.Get(protoreflect.ValueOfString("a").MapKey()).Message().Set(fd.MapValue().Message().Fields().ByName("a"), protoreflect.ValueOfInt32(10))
+		m.Mutable(protoreflect.ValueOfString("b").MapKey())
+		if m.Len() != 2 {
+			t.Errorf("%T: cloned map length = %d, want 2", dst, m.Len())
+		}
+		if !proto.Equal(src, want) {
+			t.Errorf("%T: mutating the cloned maps changed the source to %v, want %v", dst, src, want)
+		}
+	}
+
+	// Clone returns a standalone map whose nested values are copies.
+	for _, name := range []protoreflect.Name{"map_int32_int32", "map_string_nested_message"} {
+		v := src.ProtoReflect().Get(fields.ByName(name))
+		if c := v.Clone(); !c.Equal(v) {
+			t.Fatalf("Clone(%v) = %v, want %v", name, c, v)
+		}
+	}
+	m := src.ProtoReflect().Get(fields.ByName("map_int32_int32")).Clone().Map()
+	m.Set(protoreflect.ValueOfInt32(1).MapKey(), protoreflect.ValueOfInt32(20))
+	m.Clear(protoreflect.ValueOfInt32(3).MapKey())
+	if m.Len() != 1 || m.Get(protoreflect.ValueOfInt32(1).MapKey()).Int() != 20 {
+		t.Errorf("cloned map after mutation = %v", m)
+	}
+	m = src.ProtoReflect().Get(fields.ByName("map_string_nested_message")).Clone().Map()
+	m.Get(protoreflect.ValueOfString("a").MapKey()).Message().Interface().(*testpb.TestAllTypes_NestedMessage).A = proto.Int32(10)
+	m.Mutable(protoreflect.ValueOfString("b").MapKey())
+	if m.Len() != 2 {
+		t.Errorf("cloned map length = %d, want 2", m.Len())
+	}
+	if !proto.Equal(src, want) {
+		t.Errorf("mutating the cloned maps changed the source to %v, want %v", src, want)
+	}
+}
+
+func TestValueCloneInto(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		OptionalBytes: []byte("a"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)},
+		},
+	}
+	want := proto.Clone(src)
+	fields := src.ProtoReflect().Descriptor().Fields()
+	for _, dst := range []protoreflect.Message{
+		(&testpb.TestAllTypes{}).ProtoReflect(),
+		dynamicpb.NewMessage(src.ProtoReflect().Descriptor()),
+	} {
+		// A message cloned from one implementation can be stored in another.
+		for _, name := range []protoreflect.Name{"optional_bytes", "optional_nested_message"} {
+			fd := fields.ByName(name)
+			src.ProtoReflect().Get(fd).CloneInto(dst, fd)
+			if got, want := dst.Get(fd), src.ProtoReflect().Get(fd); !got.Equal(want) {
+				t.Errorf("%T: CloneInto(%v) = %v, want %v", dst, name, got, want)
+			}
+		}
+		copy(dst.Get(fields.ByName("optional_bytes")).Bytes(), "b")
+		dst.Get(fields.ByName("optional_nested_message")).Message().Clear(fields.ByName("optional_nested_message").Message().Fields().ByName("corecursive"))
+		if !proto.Equal(src, want) {
+			t.Errorf("%T: mutating the cloned fields changed the source to %v, want %v", dst, src, want)
+		}
+
+		// Cloning an invalid message clears the field.
+		fd := fields.ByName("optional_nested_message")
+		(&testpb.TestAllTypes{}).ProtoReflect().Get(fd).CloneInto(dst, fd)
+		if dst.Has(fd) {
+			t.Errorf("%T: CloneInto of an invalid message left the field populated", dst)
+		}
+	}
+}
+
+func TestCopyList(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(1)},
+			{A: proto.Int32(2)},
+		},
+		RepeatedBytes: [][]byte{[]byte("a")},
+	}
+	want := proto.Clone(src)
+	fields := src.ProtoReflect().Descriptor().Fields()
+
+	dst := &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(0)}},
+	}
+	for _, name := range []protoreflect.Name{"repeated_nested_message", "repeated_bytes"} {
+		fd := fields.ByName(name)
+		protoreflect.CopyList(dst.ProtoReflect().Mutable(fd).List(), src.ProtoReflect().Get(fd).List())
+	}
+	wantDst := &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(0)},
+			{A: proto.Int32(1)},
+			{A: proto.Int32(2)},
+		},
+		RepeatedBytes: [][]byte{[]byte("a")},
+	}
+	if !proto.Equal(dst, wantDst) {
+		t.Fatalf("CopyList produced %v, want %v", dst, wantDst)
+	}
+
+	dst.RepeatedNestedMessage[1].A = proto.Int32(10)
+	dst.RepeatedBytes[0][0] = 'A'
+	if !proto.Equal(src, want) {
+		t.Errorf("mutating the destination changed the source to %v, want %v", src, want)
+	}
+
+	// Message elements are copied into elements created by the destination,
+	// so the source and destination may use different implementations.
+	fd := fields.ByName("repeated_nested_message")
+	dyn := dynamicpb.NewMessage(src.ProtoReflect().Descriptor())
+	protoreflect.CopyList(dyn.Mutable(fd).List(), src.ProtoReflect().Get(fd).List())
+	if got, want := dyn.Get(fd).List().Len(), 2; got != want {
+		t.Errorf("CopyList into a dynamic message copied %d elements, want %d", got, want)
+	}
+	if !proto.Equal(src, want) {
+		t.Errorf("CopyList into a dynamic message changed the source to %v, want %v", src, want)
+	}
+}
+
+func TestCopyListMismatch(t *testing.T) {
+	fields := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields()
+	for _, tt := range []struct {
+		dst, src protoreflect.Name
+	}{
+		{"repeated_int32", "repeated_int64"},
+		{"repeated_nested_message", "repeated_foreign_message"},
+		{"repeated_string", "repeated_bytes"},
+	} {
+		mustPanic(t, fmt.Sprintf("CopyList(%v, %v)", tt.dst, tt.src), func() {
+			m := &testpb.TestAllTypes{}
+			protoreflect.CopyList(m.ProtoReflect().Mutable(fields.ByName(tt.dst)).List(), m.ProtoReflect().Mutable(fields.ByName(tt.src)).List())
+		})
+	}
+}
+
+func TestCopyMap(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"a": {A: proto.Int32(1)},
+			"b": {A: proto.Int32(2)},
+		},
+		MapStringBytes: map[string][]byte{"x": []byte("src")},
+	}
+	want := proto.Clone(src)
+	fields := src.ProtoReflect().Descriptor().Fields()
+
+	dst := &testpb.TestAllTypes{
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"b": {A: proto.Int32(20)},
+			"c": {A: proto.Int32(30)},
+		},
+		MapStringBytes: map[string][]byte{"x": []byte("dst"), "y": []byte("dst")},
+	}
+	for _, name := range []protoreflect.Name{"map_string_nested_message", "map_string_bytes"} {
+		fd := fields.ByName(name)
+		protoreflect.CopyMap(dst.ProtoReflect().Mutable(fd).Map(), src.ProtoReflect().Get(fd).Map())
+	}
+	wantDst := &testpb.TestAllTypes{
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"a": {A: proto.Int32(1)},
+			"b": {A: proto.Int32(2)},
+			"c": {A: proto.Int32(30)},
+		},
+		MapStringBytes: map[string][]byte{"x": []byte("src"), "y": []byte("dst")},
+	}
+	if !proto.Equal(dst, wantDst) {
+		t.Fatalf("CopyMap produced %v, want %v", dst, wantDst)
+	}
+
+	dst.MapStringNestedMessage["a"].A = proto.Int32(10)
+	dst.MapStringBytes["x"][0] = 'S'
+	if !proto.Equal(src, want) {
+		t.Errorf("mutating the destination changed the source to %v, want %v", src, want)
+	}
+}
+
+func TestCopyMapMismatch(t *testing.T) {
+	fields := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields()
+	for _, tt := range []struct {
+		dst, src *testpb.TestAllTypes
+		dstField protoreflect.Name
+		srcField protoreflect.Name
+	}{{
+		dst:      &testpb.TestAllTypes{},
+		src:      &testpb.TestAllTypes{},
+		dstField: "map_int32_int32",
+		srcField: "map_int64_int64",
+	}, {
+		dst:      &testpb.TestAllTypes{},
+		src:      &testpb.TestAllTypes{},
+		dstField: "map_string_nested_message",
+		srcField: "map_string_nested_enum",
+	}, {
+		dst:      &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 1}},
+		src:      &testpb.TestAllTypes{MapInt64Int64: map[int64]int64{1: 1}},
+		dstField: "map_int32_int32",
+		srcField: "map_int64_int64",
+	}} {
+		mustPanic(t, fmt.Sprintf("CopyMap(%v, %v)", tt.dstField, tt.srcField), func() {
+			protoreflect.CopyMap(tt.dst.ProtoReflect().Mutable(fields.ByName(tt.dstField)).Map(), tt.src.ProtoReflect().Get(fields.ByName(tt.srcField)).Map())
+		})
+	}
+
+	// Maps whose values have the same type but whose keys do not
+	// are rejected up front, even if either map is empty.
+	legacyFd := protoimpl.X.MessageDescriptorOf(&legacypb.Message{}).Fields().ByName("map_bool_int32")
+	legacyMap := func(m map[bool]int32) protoreflect.Map {
+		return protoimpl.X.ProtoMessageV2Of(&legacypb.Message{MapBoolInt32: m}).ProtoReflect().Mutable(legacyFd).Map()
+	}
+	dynamicMap := func(fd protoreflect.FieldDescriptor) protoreflect.Map {
+		return dynamicpb.NewMessage(fd.ContainingMessage()).Mutable(fd).Map()
+	}
+	for _, tt := range []struct {
+		name     string
+		dst, src protoreflect.Map
+	}{
+		{"empty maps", (&testpb.TestAllTypes{}).ProtoReflect().Mutable(fields.ByName("map_int32_int32")).Map(), legacyMap(nil)},
+		{"empty dst", (&testpb.TestAllTypes{}).ProtoReflect().Mutable(fields.ByName("map_int32_int32")).Map(), legacyMap(map[bool]int32{true: 1})},
+		{"empty src", (&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 1}}).ProtoReflect().Mutable(fields.ByName("map_int32_int32")).Map(), legacyMap(nil)},
+		{"empty dynamic maps", dynamicMap(fields.ByName("map_int32_int32")), dynamicMap(legacyFd)},
+	} {
+		mustPanic(t, fmt.Sprintf("CopyMap with %v of mismatching key types", tt.name), func() {
+			protoreflect.CopyMap(tt.dst, tt.src)
+		})
+		if tt.dst.Len() > 1 {
+			t.Errorf("CopyMap with %v of mismatching key types modified dst", tt.name)
+		}
+	}
+}
+
+func TestMergeMapFunc(t *testing.T) {
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("map_int32_int32")
+	keepDst := func(_ protoreflect.MapKey, dst, _ protoreflect.Value) protoreflect.Value { return dst }
+	keepSrc := func(_ protoreflect.MapKey, _, src protoreflect.Value) protoreflect.Value { return src }
+	sum := func(_ protoreflect.MapKey, dst, src protoreflect.Value) protoreflect.Value {
+		return protoreflect.ValueOfInt32(int32(dst.Int() + src.Int()))
+	}
+	tests := []struct {
+		name    string
+		resolve func(protoreflect.MapKey, protoreflect.Value, protoreflect.Value) protoreflect.Value
+		want    map[int32]int32
+	}{
+		{"keep dst", keepDst, map[int32]int32{1: 10, 2: 20, 3: 300}},
+		{"keep src", keepSrc, map[int32]int32{1: 10, 2: 200, 3: 300}},
+		{"sum", sum, map[int32]int32{1: 10, 2: 220, 3: 300}},
+	}
+	for _, tt := range tests {
+		dst := &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 10, 2: 20}}
+		src := &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{2: 200, 3: 300}}
+		protoreflect.MergeMapFunc(dst.ProtoReflect().Mutable(fd).Map(), src.ProtoReflect().Get(fd).Map(), tt.resolve)
+		if !reflect.DeepEqual(dst.MapInt32Int32, tt.want) {
+			t.Errorf("%v: MergeMapFunc produced %v, want %v", tt.name, dst.MapInt32Int32, tt.want)
+		}
+		if want := map[int32]int32{2: 200, 3: 300}; !reflect.DeepEqual(src.MapInt32Int32, want) {
+			t.Errorf("%v: MergeMapFunc modified src to %v, want %v", tt.name, src.MapInt32Int32, want)
+		}
+	}
+
+	// Message values absent from dst are copied rather than aliased.
+	mfd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("map_string_nested_message")
+	dst := &testpb.TestAllTypes{}
+	src := &testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{"a": {A: proto.Int32(1)}}}
+	protoreflect.MergeMapFunc(dst.ProtoReflect().Mutable(mfd).Map(), src.ProtoReflect().Get(mfd).Map(), keepDst)
+	if got := dst.MapStringNestedMessage["a"]; got == src.MapStringNestedMessage["a"] || got.GetA() != 1 {
+		t.Errorf("MergeMapFunc stored %v, want a copy of %v", got, src.MapStringNestedMessage["a"])
+	}
+
+	mustPanic(t, "MergeMapFunc on maps of different types", func() {
+		fields := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields()
+		dst := &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 1}}
+		src := &testpb.TestAllTypes{MapInt64Int64: map[int64]int64{1: 1}}
+		protoreflect.MergeMapFunc(dst.ProtoReflect().Mutable(fields.ByName("map_int32_int32")).Map(), src.ProtoReflect().Get(fields.ByName("map_int64_int64")).Map(), keepSrc)
+	})
+
+	// Key types are compared before merging, even into an empty map.
+	mustPanic(t, "MergeMapFunc into an empty map of a different key type", func() {
+		fd := protoimpl.X.MessageDescriptorOf(&legacypb.Message{}).Fields().ByName("map_bool_int32")
+		src := protoimpl.X.ProtoMessageV2Of(&legacypb.Message{MapBoolInt32: map[bool]int32{true: 1}}).ProtoReflect().Get(fd).Map()
+		dst := &testpb.TestAllTypes{}
+		protoreflect.MergeMapFunc(dst.ProtoReflect().Mutable(dst.ProtoReflect().Descriptor().Fields().ByName("map_int32_int32")).Map(), src, keepSrc)
+	})
+}
@@ -32,6 +32,9 @@ import (
 //   - [Message] values are equal if they belong to the same message descriptor,
 //     have the same set of populated known and extension field values,
 //     and the same set of unknown fields values.
+//     Messages backed by the same pointer are equal without being traversed.
+//     Identity cannot be determined for non-pointer message implementations,
+//     which are always compared field by field.
 //
 //   - [List] values are equal if they are the same length and
 //     each corresponding element is equal.
@@ -39,10 +42,32 @@ import (
 //   - [Map] values are equal if they have the same set of keys and
 //     the corresponding value for each key is equal.
 func (v1 Value) Equal(v2 Value) bool {
-	return equalValue(v1, v2)
+	return equalValue(v1, v2, equalFloat)
 }
 
-func equalValue(x, y Value) bool {
+// EqualApprox reports whether v1 and v2 are recursively equal as by
+// [Value.Equal], except that floating point values, including those
+// nested within messages, lists, and maps, are equal if the absolute
+// difference between them is at most epsilon.
+// As with Equal, a NaN is equal only to another NaN, and
+// an infinity is equal only to an infinity of the same sign,
+// even if epsilon is itself infinite.
+// A negative epsilon allows no difference, so that floating point values
+// must be equal as by Equal.
+// Values of different types, such as float32 and float64, are always unequal.
+func (v1 Value) EqualApprox(v2 Value, epsilon float64) bool {
+	return equalValue(v1, v2, func(x, y float64) bool {
+		if equalFloat(x, y) {
+			return true
+		}
+		if math.IsInf(x, 0) || math.IsInf(y, 0) {
+			return false
+		}
+		return math.Abs(x-y) <= epsilon
+	})
+}
+
+func equalValue(x, y Value, eqFloat func(x, y float64) bool) bool {
 	eqType := x.typ == y.typ
 	switch x.typ {
 	case nilType:
@@ -54,7 +79,7 @@ func equalValue(x, y Value) bool {
 	case uint32Type, uint64Type:
 		return eqType && x.Uint() == y.Uint()
 	case float32Type, float64Type:
-		return eqType && equalFloat(x.Float(), y.Float())
+		return eqType && eqFloat(x.Float(), y.Float())
 	case stringType:
 		return eqType && x.String() == y.String()
 	case bytesType:
@@ -65,19 +90,48 @@ func equalValue(x, y Value) bool {
 		switch x := x.Interface().(type) {
 		case Message:
 			y, ok := y.Interface().(Message)
-			return ok && equalMessage(x, y)
+			return ok && equalMessage(x, y, eqFloat)
 		case List:
 			y, ok := y.Interface().(List)
-			return ok && equalList(x, y)
+			return ok && equalList(x, y, eqFloat)
 		case Map:
 			y, ok := y.Interface().(Map)
-			return ok && equalMap(x, y)
+			return ok && equalMap(x, y, eqFloat)
 		default:
 			panic(fmt.Sprintf("unknown type: %T", x))
 		}
 	}
 }
 
+// EqualFields reports whether x and y have equal values for each of the
+// fields with the given numbers, which must be declared in the message
+// descriptor of x. Other fields, including unknown fields, are ignored.
+// A field is equal if it is unpopulated in both messages, or populated
+// in both with values that are equal according to [Value.Equal].
+// Messages with different descriptors are never equal.
+//
+// It panics if any number is not the number of a field declared in
+// the message descriptor of x. Extension fields are not supported.
+func EqualFields(x, y Message, nums []FieldNumber) bool {
+	fields := x.Descriptor().Fields()
+	for _, num := range nums {
+		if fields.ByNumber(num) == nil {
+			panic(fmt.Sprintf("invalid field number %d for message %v", num, x.Descriptor().FullName()))
+		}
+	}
+	if x.Descriptor() != y.Descriptor() {
+		return false
+	}
+	for _, num := range nums {
+		fd := fields.ByNumber(num)
+		hx, hy := x.Has(fd), y.Has(fd)
+		if hx != hy || (hx && !equalValue(x.Get(fd), y.Get(fd), equalFloat)) {
+			return false
+		}
+	}
+	return true
+}
+
 // equalFloat compares two floats, where NaNs are treated as equal.
 func equalFloat(x, y float64) bool {
 	if math.IsNaN(x) || math.IsNaN(y) {
@@ -87,7 +141,10 @@ func equalFloat(x, y float64) bool {
 }
 
 // equalMessage compares two messages.
-func equalMessage(mx, my Message) bool {
+func equalMessage(mx, my Message, eqFloat func(x, y float64) bool) bool {
+	if sameMessage(mx, my) {
+		return true
+	}
 	if mx.Descriptor() != my.Descriptor() {
 		return false
 	}
@@ -97,7 +154,7 @@ func equalMessage(mx, my Message) bool {
 	mx.Range(func(fd FieldDescriptor, vx Value) bool {
 		nx++
 		vy := my.Get(fd)
-		equal = my.Has(fd) && equalValue(vx, vy)
+		equal = my.Has(fd) && equalValue(vx, vy, eqFloat)
 		return equal
 	})
 	if !equal {
@@ -115,13 +172,23 @@ func equalMessage(mx, my Message) bool {
 	return equalUnknown(mx.GetUnknown(), my.GetUnknown())
 }
 
+// sameMessage reports whether mx and my are backed by the same pointer.
+// Only pointers are compared since other concrete types may not be comparable.
+func sameMessage(mx, my Message) bool {
+	px, py := mx.Interface(), my.Interface()
+	if px == nil || py == nil {
+		return false
+	}
+	return reflect.TypeOf(px).Kind() == reflect.Ptr && px == py
+}
+
 // equalList compares two lists.
-func equalList(x, y List) bool {
+func equalList(x, y List, eqFloat func(x, y float64) bool) bool {
 	if x.Len() != y.Len() {
 		return false
 	}
 	for i := x.Len() - 1; i >= 0; i-- {
-		if !equalValue(x.Get(i), y.Get(i)) {
+		if !equalValue(x.Get(i), y.Get(i), eqFloat) {
 			return false
 		}
 	}
@@ -129,14 +196,14 @@ func equalList(x, y List) bool {
 }
 
 // equalMap compares two maps.
-func equalMap(x, y Map) bool {
+func equalMap(x, y Map, eqFloat func(x, y float64) bool) bool {
 	if x.Len() != y.Len() {
 		return false
 	}
 	equal := true
 	x.Range(func(k MapKey, vx Value) bool {
 		vy := y.Get(k)
-		equal = y.Has(k) && equalValue(vx, vy)
+		equal = y.Has(k) && equalValue(vx, vy, eqFloat)
 		return equal
 	})
 	return equal
@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestValueEqualApprox(t *testing.T) {
+	msg := func(m *testpb.TestAllTypes) protoreflect.Value {
+		return protoreflect.ValueOfMessage(m.ProtoReflect())
+	}
+	const eps = 1e-6
+	tests := []struct {
+		name string
+		x, y protoreflect.Value
+		want bool
+	}{
+		{"float64 within epsilon", protoreflect.ValueOfFloat64(1), protoreflect.ValueOfFloat64(1 + eps/2), true},
+		{"float64 at epsilon", protoreflect.ValueOfFloat64(0), protoreflect.ValueOfFloat64(eps), true},
+		{"float64 beyond epsilon", protoreflect.ValueOfFloat64(1), protoreflect.ValueOfFloat64(1 + 2*eps), false},
+		{"float32 within epsilon", protoreflect.ValueOfFloat32(0.1), protoreflect.ValueOfFloat32(0.1 + eps/2), true},
+		{"float32 and float64", protoreflect.ValueOfFloat32(1), protoreflect.ValueOfFloat64(1), false},
+		{"NaN and NaN", protoreflect.ValueOfFloat64(math.NaN()), protoreflect.ValueOfFloat64(math.NaN()), true},
+		{"NaN and number", protoreflect.ValueOfFloat64(math.NaN()), protoreflect.ValueOfFloat64(0), false},
+		{"same infinities", protoreflect.ValueOfFloat64(math.Inf(1)), protoreflect.ValueOfFloat64(math.Inf(1)), true},
+		{"opposite infinities", protoreflect.ValueOfFloat64(math.Inf(1)), protoreflect.ValueOfFloat64(math.Inf(-1)), false},
+		{"infinity and max", protoreflect.ValueOfFloat64(math.Inf(1)), protoreflect.ValueOfFloat64(math.MaxFloat64), false},
+		{"ints are exact", protoreflect.ValueOfInt64(1), protoreflect.ValueOfInt64(2), false},
+		{"strings are exact", protoreflect.ValueOfString("a"), protoreflect.ValueOfString("a"), true},
+		{
+			"nested message",
+			msg(&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalDouble: proto.Float64(1)}}}),
+			msg(&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalDouble: proto.Float64(1 + eps/2)}}}),
+			true,
+		},
+		{
+			"nested message beyond epsilon",
+			msg(&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalDouble: proto.Float64(1)}}}),
+			msg(&testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalDouble: proto.Float64(1.1)}}}),
+			false,
+		},
+		{
+			"lists",
+			msg(&testpb.TestAllTypes{RepeatedFloat: []float32{1, 2}, RepeatedDouble: []float64{3}}),
+			msg(&testpb.TestAllTypes{RepeatedFloat: []float32{1, 2 + eps/4}, RepeatedDouble: []float64{3 - eps/2}}),
+			true,
+		},
+		{
+			"lists of different lengths",
+			msg(&testpb.TestAllTypes{RepeatedDouble: []float64{3}}),
+			msg(&testpb.TestAllTypes{RepeatedDouble: []float64{3, 3}}),
+			false,
+		},
+		{
+			"maps",
+			msg(&testpb.TestAllTypes{MapInt32Double: map[int32]float64{1: 1, 2: 2}}),
+			msg(&testpb.TestAllTypes{MapInt32Double: map[int32]float64{1: 1 + eps/2, 2: 2}}),
+			true,
+		},
+		{
+			"maps with different keys",
+			msg(&testpb.TestAllTypes{MapInt32Double: map[int32]float64{1: 1}}),
+			msg(&testpb.TestAllTypes{MapInt32Double: map[int32]float64{2: 1}}),
+			false,
+		},
+		{
+			"non-float field differs",
+			msg(&testpb.TestAllTypes{OptionalDouble: proto.Float64(1), OptionalInt32: proto.Int32(1)}),
+			msg(&testpb.TestAllTypes{OptionalDouble: proto.Float64(1), OptionalInt32: proto.Int32(2)}),
+			false,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.x.EqualApprox(tt.y, eps); got != tt.want {
+			t.Errorf("%v: Value(%v).EqualApprox(%v, %v) = %v, want %v", tt.name, tt.x, tt.y, eps, got, tt.want)
+		}
+		if got := tt.y.EqualApprox(tt.x, eps); got != tt.want {
+			t.Errorf("%v: Value(%v).EqualApprox(%v, %v) = %v, want %v", tt.name, tt.y, tt.x, eps, got, tt.want)
+		}
+		if tt.x.Equal(tt.y) && !tt.want {
+			t.Errorf("%v: values are Equal but not EqualApprox", tt.name)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		x, y float64
+		eps  float64
+		want bool
+	}{
+		// An infinite epsilon does not make a finite value equal to an infinity.
+		{"finite and infinity with infinite epsilon", 1, math.Inf(1), math.Inf(1), false},
+		{"infinities with infinite epsilon", math.Inf(-1), math.Inf(-1), math.Inf(1), true},
+		{"opposite infinities with infinite epsilon", math.Inf(1), math.Inf(-1), math.Inf(1), false},
+		{"distant values with infinite epsilon", -math.MaxFloat64, math.MaxFloat64, math.Inf(1), true},
+
+		// A negative epsilon requires exact equality.
+		{"equal values with negative epsilon", 1, 1, -1, true},
+		{"close values with negative epsilon", 1, math.Nextafter(1, 2), -1, false},
+		{"NaNs with negative epsilon", math.NaN(), math.NaN(), -1, true},
+	} {
+		x, y := protoreflect.ValueOfFloat64(tt.x), protoreflect.ValueOfFloat64(tt.y)
+		if got := x.EqualApprox(y, tt.eps); got != tt.want {
+			t.Errorf("%v: Value(%v).EqualApprox(%v, %v) = %v, want %v", tt.name, x, y, tt.eps, got, tt.want)
+		}
+		if got := y.EqualApprox(x, tt.eps); got != tt.want {
+			t.Errorf("%v: Value(%v).EqualApprox(%v, %v) = %v, want %v", tt.name, y, x, tt.eps, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+)
+
+func TestSetFields(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalString: proto.String("clear me"),
+	}
+	protoreflect.SetFields(m.ProtoReflect(), map[protoreflect.FieldNumber]protoreflect.Value{
+		1:  protoreflect.ValueOfInt32(1),
+		14: {},
+		18: protoreflect.ValueOfMessage((&testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}).ProtoReflect()),
+	})
+	want := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("SetFields produced %v, want %v", m, want)
+	}
+}
+
+func TestSetFieldsOneof(t *testing.T) {
+	// Members of the same oneof are set in ascending order of field number,
+	// so the member with the largest number is the one left populated.
+	for i := 0; i < 10; i++ {
+		m := &testpb.TestAllTypes{}
+		protoreflect.SetFields(m.ProtoReflect(), map[protoreflect.FieldNumber]protoreflect.Value{
+			113: protoreflect.ValueOfString("string"),
+			111: protoreflect.ValueOfUint32(111),
+			112: protoreflect.ValueOfMessage((&testpb.TestAllTypes_NestedMessage{}).ProtoReflect()),
+		})
+		if got, ok := m.OneofField.(*testpb.TestAllTypes_OneofString); !ok || got.OneofString != "string" {
+			t.Fatalf("SetFields left oneof set to %v, want oneof_string", m.OneofField)
+		}
+	}
+
+	// Clearing an unpopulated member of a oneof does not affect
+	// the member that is populated.
+	m := &testpb.TestAllTypes{}
+	protoreflect.SetFields(m.ProtoReflect(), map[protoreflect.FieldNumber]protoreflect.Value{
+		111: protoreflect.ValueOfUint32(111),
+		113: {},
+	})
+	if got, ok := m.OneofField.(*testpb.TestAllTypes_OneofUint32); !ok || got.OneofUint32 != 111 {
+		t.Errorf("SetFields left oneof set to %v, want oneof_uint32", m.OneofField)
+	}
+}
+
+func TestSetFieldsUnknownNumber(t *testing.T) {
+	m := &testpb.TestAllTypes{OptionalInt32: proto.Int32(5)}
+	want := proto.Clone(m)
+	mustPanic(t, "SetFields with an unknown field number", func() {
+		protoreflect.SetFields(m.ProtoReflect(), map[protoreflect.FieldNumber]protoreflect.Value{
+			1:     protoreflect.ValueOfInt32(1),
+			14:    protoreflect.ValueOfString("hello"),
+			99999: protoreflect.ValueOfInt32(2),
+		})
+	})
+	if !proto.Equal(m, want) {
+		t.Errorf("SetFields modified the message to %v before panicking, want %v", m, want)
+	}
+}
+
+func TestRangeFields(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:          proto.Int32(1),
+		OptionalNestedMessage:  &testpb.TestAllTypes_NestedMessage{},
+		OptionalForeignMessage: &testpb.ForeignMessage{},
+		RepeatedInt32:          []int32{1},
+		RepeatedNestedMessage:  []*testpb.TestAllTypes_NestedMessage{{}},
+		MapInt32Int32:          map[int32]int32{1: 1},
+		OneofField:             &testpb.TestAllTypes_OneofNestedMessage{},
+	}
+	collect := func(keep func(protoreflect.FieldDescriptor) bool) map[protoreflect.Name]bool {
+		got := make(map[protoreflect.Name]bool)
+		protoreflect.RangeFields(m.ProtoReflect(), keep, func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			got[fd.Name()] = true
+			return true
+		})
+		return got
+	}
+
+	tests := []struct {
+		name string
+		keep func(protoreflect.FieldDescriptor) bool
+		want map[protoreflect.Name]bool
+	}{{
+		name: "singular messages",
+		keep: func(fd protoreflect.FieldDescriptor) bool {
+			return fd.Kind() == protoreflect.MessageKind && fd.Cardinality() != protoreflect.Repeated
+		},
+		want: map[protoreflect.Name]bool{
+			"optional_nested_message":  true,
+			"optional_foreign_message": true,
+			"oneof_nested_message":     true,
+		},
+	}, {
+		name: "repeated",
+		keep: func(fd protoreflect.FieldDescriptor) bool {
+			return fd.Cardinality() == protoreflect.Repeated
+		},
+		want: map[protoreflect.Name]bool{
+			"repeated_int32":          true,
+			"repeated_nested_message": true,
+			"map_int32_int32":         true,
+		},
+	}, {
+		name: "lists only",
+		keep: protoreflect.FieldDescriptor.IsList,
+		want: map[protoreflect.Name]bool{
+			"repeated_int32":          true,
+			"repeated_nested_message": true,
+		},
+	}, {
+		name: "none",
+		keep: func(protoreflect.FieldDescriptor) bool { return false },
+		want: map[protoreflect.Name]bool{},
+	}}
+	for _, tt := range tests {
+		got := collect(tt.keep)
+		if len(got) != len(tt.want) {
+			t.Errorf("%v: RangeFields visited %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for name := range tt.want {
+			if !got[name] {
+				t.Errorf("%v: RangeFields visited %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Returning false from f stops the iteration.
+	n := 0
+	protoreflect.RangeFields(m.ProtoReflect(), func(protoreflect.FieldDescriptor) bool { return true }, func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("RangeFields called f %d times after it returned false, want 1", n)
+	}
+}
+
+func TestEqualFields(t *testing.T) {
+	x := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalString:        proto.String("same"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+		RepeatedInt32:         []int32{1, 2},
+	}
+	y := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(2),
+		OptionalString:        proto.String("same"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+		OptionalInt64:         proto.Int64(0),
+		RepeatedInt32:         []int32{1, 3},
+	}
+	tests := []struct {
+		nums []protoreflect.FieldNumber
+		want bool
+	}{
+		{nil, true},
+		{[]protoreflect.FieldNumber{14}, true},        // optional_string: equal
+		{[]protoreflect.FieldNumber{14, 18}, true},    // optional_nested_message: deeply equal
+		{[]protoreflect.FieldNumber{3}, true},         // optional_uint32: unset in both
+		{[]protoreflect.FieldNumber{14, 1}, false},    // optional_int32: different
+		{[]protoreflect.FieldNumber{2}, false},        // optional_int64: set only in y
+		{[]protoreflect.FieldNumber{31}, false},       // repeated_int32: different
+		{[]protoreflect.FieldNumber{14, 18, 3}, true}, // all equal
+	}
+	for _, tt := range tests {
+		if got := protoreflect.EqualFields(x.ProtoReflect(), y.ProtoReflect(), tt.nums); got != tt.want {
+			t.Errorf("EqualFields(x, y, %v) = %v, want %v", tt.nums, got, tt.want)
+		}
+	}
+
+	if protoreflect.EqualFields(x.ProtoReflect(), (&testpb.ForeignMessage{}).ProtoReflect(), nil) {
+		t.Errorf("EqualFields on messages of different types = true, want false")
+	}
+	mustPanic(t, "EqualFields with an unknown field number", func() {
+		protoreflect.EqualFields(x.ProtoReflect(), y.ProtoReflect(), []protoreflect.FieldNumber{14, 99999})
+	})
+}
+
+func TestRangePresence(t *testing.T) {
+	type presence struct{ populated, hasPresence bool }
+	m := &test3pb.TestAllTypes{
+		SingularInt32:  1,
+		SingularString: "", // implicit presence: indistinguishable from unset
+		OptionalInt32:  proto.Int32(0),
+		RepeatedInt32:  []int32{1},
+		OneofField:     &test3pb.TestAllTypes_OneofUint32{OneofUint32: 0},
+	}
+	got := make(map[protoreflect.Name]presence)
+	protoreflect.RangePresence(m.ProtoReflect(), func(fd protoreflect.FieldDescriptor, populated, hasPresence bool) bool {
+		got[fd.Name()] = presence{populated, hasPresence}
+		return true
+	})
+	if n := m.ProtoReflect().Descriptor().Fields().Len(); len(got) != n {
+		t.Errorf("RangePresence visited %d fields, want %d", len(got), n)
+	}
+	for name, want := range map[protoreflect.Name]presence{
+		"singular_int32":          {true, false},
+		"singular_string":         {false, false},
+		"singular_nested_message": {false, true},
+		"optional_int32":          {true, true},
+		"optional_int64":          {false, true},
+		"repeated_int32":          {true, false},
+		"repeated_int64":          {false, false},
+		"oneof_uint32":            {true, true},
+		"oneof_string":            {false, true},
+	} {
+		if got[name] != want {
+			t.Errorf("RangePresence reported %v as %+v, want %+v", name, got[name], want)
+		}
+	}
+
+	n := 0
+	protoreflect.RangePresence(m.ProtoReflect(), func(protoreflect.FieldDescriptor, bool, bool) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("RangePresence called f %d times after it returned false, want 1", n)
+	}
+}
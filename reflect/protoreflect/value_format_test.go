@@ -0,0 +1,97 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestValueDebugString(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32:  []int32{1, 2, 3},
+		MapInt32Int32:  map[int32]int32{1: 1, 2: 2},
+		OptionalString: new(string),
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	var nilMsg *testpb.TestAllTypes
+
+	tests := []struct {
+		in   protoreflect.Value
+		want string
+	}{
+		{protoreflect.Value{}, "<null>"},
+		{protoreflect.ValueOfBool(true), "true"},
+		{protoreflect.ValueOfInt32(-32), "-32"},
+		{protoreflect.ValueOfInt64(math.MinInt64), "-9223372036854775808"},
+		{protoreflect.ValueOfUint32(32), "32"},
+		{protoreflect.ValueOfUint64(math.MaxUint64), "18446744073709551615"},
+		{protoreflect.ValueOfFloat32(1.1), "1.1"},
+		{protoreflect.ValueOfFloat64(math.Inf(-1)), "-Inf"},
+		{protoreflect.ValueOfString("hello\n"), `"hello\n"`},
+		{protoreflect.ValueOfString(""), `""`},
+		{protoreflect.ValueOfBytes(nil), "bytes[len 0] "},
+		{protoreflect.ValueOfBytes([]byte("hello")), "bytes[len 5] 68656c6c6f"},
+		{protoreflect.ValueOfBytes([]byte("0123456789abcdefXYZ")), "bytes[len 19] 30313233343536373839616263646566..."},
+		{protoreflect.ValueOfEnum(5), "enum(5)"},
+		{protoreflect.ValueOfMessage(m.ProtoReflect()), "<Message goproto.proto.test.TestAllTypes>"},
+		{protoreflect.ValueOfMessage(nilMsg.ProtoReflect()), "<Message goproto.proto.test.TestAllTypes>"},
+		{m.ProtoReflect().Get(fields.ByName("repeated_int32")), "[len 3]"},
+		{m.ProtoReflect().Get(fields.ByName("repeated_int64")), "[len 0]"},
+		{m.ProtoReflect().Get(fields.ByName("map_int32_int32")), "{len 2}"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.DebugString(); got != tt.want {
+			t.Errorf("Value(%v).DebugString() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnumString(t *testing.T) {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name:    "alias.proto"
+		package: "test"
+		syntax:  "proto3"
+		enum_type: [{
+			name:    "E"
+			value:   [{name:"ZERO" number:0}, {name:"ONE" number:1}, {name:"UNO" number:1}]
+			options: {allow_alias:true}
+		}]
+	`), fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliased := dynamicpb.NewEnumType(fd.Enums().Get(0))
+
+	tests := []struct {
+		in   protoreflect.Enum
+		want string
+	}{
+		{testpb.TestAllTypes_FOO, "FOO"},
+		{testpb.TestAllTypes_NEG, "NEG"},
+		{testpb.TestAllTypes_NestedEnum(1000), "1000"},
+		{testpb.TestAllTypes_NestedEnum(-5), "-5"},
+		{aliased.New(0), "ZERO"},
+		{aliased.New(1), "ONE"},
+		{aliased.New(2), "2"},
+	}
+	for _, tt := range tests {
+		if got := protoreflect.EnumString(tt.in); got != tt.want {
+			t.Errorf("EnumString(%v(%d)) = %q, want %q", tt.in.Descriptor().FullName(), tt.in.Number(), got, tt.want)
+		}
+	}
+}
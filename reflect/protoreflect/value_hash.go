@@ -0,0 +1,135 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect
+
+import (
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Tags that identify the type of each value in the output of [Value.Hash].
+const (
+	hashNil byte = iota
+	hashBool
+	hashInt32
+	hashInt64
+	hashUint32
+	hashUint64
+	hashFloat32
+	hashFloat64
+	hashString
+	hashBytes
+	hashEnum
+	hashMessage
+	hashList
+	hashMap
+)
+
+// Hash writes a canonical byte representation of v to h.
+// Values that are equal according to [Value.Equal] always write
+// the same bytes, so the resulting sum may be used for content addressing.
+// Distinct values may still produce the same sum if h has collisions.
+//
+//   - Every value is prefixed by a tag identifying its Go type,
+//     so values of different types write different bytes.
+//
+//   - Floating point values are written by their IEEE 754 bits,
+//     except that every NaN is written as the same canonical NaN
+//     and negative zero is written as positive zero.
+//
+//   - Bytes values are written by their raw contents, so that
+//     empty bytes (regardless of nil-ness) write the same bytes.
+//
+//   - [Message] values are written as their full name followed by the
+//     populated fields in ascending order of field number, and then the
+//     unknown fields stably sorted by field number (see [RawFields.Sorted]).
+//
+//   - [List] values are written in order and [Map] values are written
+//     in the sorted order of their keys (see [MapKeys]).
+//
+// The representation is stable for a given version of this module,
+// but is not guaranteed to be stable across versions.
+func (v Value) Hash(h hash.Hash) {
+	h.Write(appendValueHash(nil, v))
+}
+
+func appendValueHash(b []byte, v Value) []byte {
+	switch v.typ {
+	case nilType:
+		return append(b, hashNil)
+	case boolType:
+		return protowire.AppendVarint(append(b, hashBool), protowire.EncodeBool(v.Bool()))
+	case int32Type:
+		return protowire.AppendVarint(append(b, hashInt32), uint64(v.Int()))
+	case int64Type:
+		return protowire.AppendVarint(append(b, hashInt64), uint64(v.Int()))
+	case uint32Type:
+		return protowire.AppendVarint(append(b, hashUint32), v.Uint())
+	case uint64Type:
+		return protowire.AppendVarint(append(b, hashUint64), v.Uint())
+	case float32Type:
+		return protowire.AppendFixed64(append(b, hashFloat32), canonicalFloatBits(v.Float()))
+	case float64Type:
+		return protowire.AppendFixed64(append(b, hashFloat64), canonicalFloatBits(v.Float()))
+	case stringType:
+		return protowire.AppendString(append(b, hashString), v.String())
+	case bytesType:
+		return protowire.AppendBytes(append(b, hashBytes), v.Bytes())
+	case enumType:
+		return protowire.AppendVarint(append(b, hashEnum), uint64(v.Enum()))
+	default:
+		switch x := v.Interface().(type) {
+		case Message:
+			return appendMessageHash(append(b, hashMessage), x)
+		case List:
+			b = protowire.AppendVarint(append(b, hashList), uint64(x.Len()))
+			for i := 0; i < x.Len(); i++ {
+				b = appendValueHash(b, x.Get(i))
+			}
+			return b
+		case Map:
+			b = protowire.AppendVarint(append(b, hashMap), uint64(x.Len()))
+			for _, k := range MapKeys(x) {
+				b = appendValueHash(b, k.Value())
+				b = appendValueHash(b, x.Get(k))
+			}
+			return b
+		default:
+			panic(fmt.Sprintf("unknown type: %T", x))
+		}
+	}
+}
+
+func appendMessageHash(b []byte, m Message) []byte {
+	b = protowire.AppendString(b, string(m.Descriptor().FullName()))
+	var fds []FieldDescriptor
+	m.Range(func(fd FieldDescriptor, _ Value) bool {
+		fds = append(fds, fd)
+		return true
+	})
+	sort.Slice(fds, func(i, j int) bool { return fds[i].Number() < fds[j].Number() })
+	b = protowire.AppendVarint(b, uint64(len(fds)))
+	for _, fd := range fds {
+		b = protowire.AppendVarint(b, uint64(fd.Number()))
+		b = appendValueHash(b, m.Get(fd))
+	}
+	return protowire.AppendBytes(b, m.GetUnknown().Sorted())
+}
+
+// canonicalFloatBits returns the bits of f, mapping every NaN to the same
+// value and negative zero to positive zero.
+func canonicalFloatBits(f float64) uint64 {
+	switch {
+	case math.IsNaN(f):
+		return math.Float64bits(math.NaN())
+	case f == 0:
+		return 0
+	}
+	return math.Float64bits(f)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func hashOf(v protoreflect.Value) []byte {
+	h := sha256.New()
+	v.Hash(h)
+	return h.Sum(nil)
+}
+
+func TestValueHashEqual(t *testing.T) {
+	unknown := func(nums ...protowire.Number) []byte {
+		var b []byte
+		for _, num := range nums {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(num))
+		}
+		return b
+	}
+	newMessage := func(keys []string, unknownNums ...protowire.Number) protoreflect.Value {
+		m := &testpb.TestAllTypes{
+			OptionalInt32:         proto.Int32(1),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+			RepeatedString:        []string{"x", "y"},
+			MapStringString:       make(map[string]string),
+		}
+		for _, k := range keys {
+			m.MapStringString[k] = k + "-value"
+		}
+		m.ProtoReflect().SetUnknown(unknown(unknownNums...))
+		return protoreflect.ValueOfMessage(m.ProtoReflect())
+	}
+
+	tests := []struct {
+		name string
+		x, y protoreflect.Value
+	}{
+		{"nil", protoreflect.Value{}, protoreflect.Value{}},
+		{"int32", protoreflect.ValueOfInt32(-1), protoreflect.ValueOfInt32(-1)},
+		{"NaN payloads", protoreflect.ValueOfFloat64(math.NaN()), protoreflect.ValueOfFloat64(math.Float64frombits(0x7ff8000000000001))},
+		{"float32 NaN", protoreflect.ValueOfFloat32(float32(math.NaN())), protoreflect.ValueOfFloat32(-float32(math.NaN()))},
+		{"signed zero", protoreflect.ValueOfFloat64(0), protoreflect.ValueOfFloat64(math.Copysign(0, -1))},
+		{"nil and empty bytes", protoreflect.ValueOfBytes(nil), protoreflect.ValueOfBytes([]byte{})},
+		{"map insertion order", newMessage([]string{"a", "b", "c", "d", "e", "f"}), newMessage([]string{"f", "e", "d", "c", "b", "a"})},
+		{"unknown field order", newMessage(nil, 1000, 2000, 1000), newMessage(nil, 2000, 1000, 1000)},
+	}
+	for _, tt := range tests {
+		if !tt.x.Equal(tt.y) {
+			t.Errorf("%v: values are not equal", tt.name)
+			continue
+		}
+		for i := 0; i < 10; i++ {
+			if hx, hy := hashOf(tt.x), hashOf(tt.y); !bytes.Equal(hx, hy) {
+				t.Errorf("%v: Hash = %x and %x for equal values", tt.name, hx, hy)
+				break
+			}
+		}
+	}
+}
+
+func TestValueHashDistinct(t *testing.T) {
+	values := []protoreflect.Value{
+		{},
+		protoreflect.ValueOfBool(false),
+		protoreflect.ValueOfBool(true),
+		protoreflect.ValueOfInt32(1),
+		protoreflect.ValueOfInt64(1),
+		protoreflect.ValueOfUint32(1),
+		protoreflect.ValueOfUint64(1),
+		protoreflect.ValueOfFloat32(1),
+		protoreflect.ValueOfFloat64(1),
+		protoreflect.ValueOfFloat64(math.NaN()),
+		protoreflect.ValueOfString(""),
+		protoreflect.ValueOfString("1"),
+		protoreflect.ValueOfBytes([]byte("1")),
+		protoreflect.ValueOfEnum(1),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{OptionalInt32: proto.Int32(1)}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{OptionalInt64: proto.Int64(1)}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{RepeatedInt32: []int32{2, 1}}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 2}}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.TestAllTypes{MapInt32Int32: map[int32]int32{2: 1}}).ProtoReflect()),
+		protoreflect.ValueOfMessage((&testpb.ForeignMessage{}).ProtoReflect()),
+	}
+	seen := make(map[string]int)
+	for i, v := range values {
+		h := string(hashOf(v))
+		if j, ok := seen[h]; ok {
+			t.Errorf("Hash of values[%d] (%v) equals Hash of values[%d] (%v)", i, v, j, values[j])
+		}
+		seen[h] = i
+	}
+}
@@ -0,0 +1,551 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestFilterList(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32: []int32{0, 1, 2, 3, 4, 5},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(0)},
+			{A: proto.Int32(1)},
+			{A: proto.Int32(2)},
+		},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+
+	var indexes []int
+	protoreflect.FilterList(m.ProtoReflect().Mutable(fields.ByName("repeated_int32")).List(), func(i int, v protoreflect.Value) bool {
+		indexes = append(indexes, i)
+		return v.Int()%2 == 0
+	})
+	if got, want := m.RepeatedInt32, []int32{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterList(repeated_int32) = %v, want %v", got, want)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("FilterList called keep with indexes %v, want %v", indexes, want)
+	}
+
+	protoreflect.FilterList(m.ProtoReflect().Mutable(fields.ByName("repeated_nested_message")).List(), func(i int, _ protoreflect.Value) bool {
+		return i%2 == 1
+	})
+	want := []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(1)}}
+	if len(m.RepeatedNestedMessage) != len(want) || !proto.Equal(m.RepeatedNestedMessage[0], want[0]) {
+		t.Errorf("FilterList(repeated_nested_message) = %v, want %v", m.RepeatedNestedMessage, want)
+	}
+
+	for _, name := range []protoreflect.Name{"repeated_int32", "repeated_nested_message"} {
+		l := m.ProtoReflect().Mutable(fields.ByName(name)).List()
+		protoreflect.FilterList(l, func(int, protoreflect.Value) bool { return false })
+		if l.Len() != 0 {
+			t.Errorf("FilterList(%v) to empty left %d elements", name, l.Len())
+		}
+	}
+}
+
+func TestSubList(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32: []int32{0, 1, 2, 3, 4, 5},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(0)},
+			{A: proto.Int32(1)},
+			{A: proto.Int32(2)},
+		},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	l := m.ProtoReflect().Mutable(fields.ByName("repeated_int32")).List()
+
+	sub := protoreflect.SubList(l, 2, 5)
+	if got, want := sub.Len(), 3; got != want {
+		t.Fatalf("SubList(l, 2, 5).Len() = %d, want %d", got, want)
+	}
+	for i := 0; i < sub.Len(); i++ {
+		if got, want := sub.Get(i).Int(), int64(i+2); got != want {
+			t.Errorf("SubList(l, 2, 5).Get(%d) = %d, want %d", i, got, want)
+		}
+	}
+
+	sub.Set(0, protoreflect.ValueOfInt32(20))
+	protoreflect.SubList(sub, 1, 3).Set(1, protoreflect.ValueOfInt32(40))
+	if got, want := m.RepeatedInt32, []int32{0, 1, 20, 3, 40, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("writes through SubList produced %v, want %v", got, want)
+	}
+
+	sub.Truncate(1)
+	if sub.Len() != 1 || l.Len() != 6 {
+		t.Errorf("after Truncate(1), view length = %d and parent length = %d, want 1 and 6", sub.Len(), l.Len())
+	}
+
+	ml := m.ProtoReflect().Mutable(fields.ByName("repeated_nested_message")).List()
+	msub := protoreflect.SubList(ml, 1, 3)
+	msub.Get(0).Message().Set(fields.ByName("optional_nested_message").Message().Fields().ByName("a"), protoreflect.ValueOfInt32(10))
+	if got := m.RepeatedNestedMessage[1].GetA(); got != 10 {
+		t.Errorf("write through message SubList produced a = %d, want 10", got)
+	}
+	if got := protoreflect.SubList(ml, 3, 3).Len(); got != 0 {
+		t.Errorf("SubList(ml, 3, 3).Len() = %d, want 0", got)
+	}
+}
+
+func TestSubListPanics(t *testing.T) {
+	m := &testpb.TestAllTypes{RepeatedInt32: []int32{0, 1, 2}}
+	l := m.ProtoReflect().Mutable(m.ProtoReflect().Descriptor().Fields().ByName("repeated_int32")).List()
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"negative low", func() { protoreflect.SubList(l, -1, 2) }},
+		{"high before low", func() { protoreflect.SubList(l, 2, 1) }},
+		{"high past end", func() { protoreflect.SubList(l, 0, 4) }},
+		{"Get past view end", func() { protoreflect.SubList(l, 0, 2).Get(2) }},
+		{"Set negative index", func() { protoreflect.SubList(l, 1, 2).Set(-1, protoreflect.ValueOfInt32(0)) }},
+		{"Append", func() { protoreflect.SubList(l, 0, 1).Append(protoreflect.ValueOfInt32(0)) }},
+		{"Truncate longer", func() { protoreflect.SubList(l, 0, 1).Truncate(2) }},
+	}
+	for _, tt := range tests {
+		mustPanic(t, tt.name, tt.f)
+	}
+}
+
+func TestListRange(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32: []int32{0, 1, 2, 3, 4},
+		RepeatedBytes: [][]byte{[]byte("a"), []byte("b")},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	l := m.ProtoReflect().Get(fields.ByName("repeated_int32")).List()
+
+	tests := []struct {
+		low, high int
+		want      []int32
+	}{
+		{0, 0, nil},
+		{5, 5, nil},
+		{0, 5, []int32{0, 1, 2, 3, 4}},
+		{1, 4, []int32{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got := protoreflect.ListRange(l, tt.low, tt.high)
+		if len(got) != len(tt.want) {
+			t.Errorf("ListRange(l, %d, %d) returned %d elements, want %d", tt.low, tt.high, len(got), len(tt.want))
+			continue
+		}
+		for i, v := range got {
+			if int32(v.Int()) != tt.want[i] {
+				t.Errorf("ListRange(l, %d, %d)[%d] = %v, want %v", tt.low, tt.high, i, v, tt.want[i])
+			}
+		}
+	}
+
+	// Modifying the snapshot does not affect the list.
+	got := protoreflect.ListRange(l, 0, 2)
+	got[0] = protoreflect.ValueOfInt32(100)
+	if m.RepeatedInt32[0] != 0 {
+		t.Errorf("modifying ListRange result changed the list to %v", m.RepeatedInt32)
+	}
+	bl := m.ProtoReflect().Get(fields.ByName("repeated_bytes")).List()
+	protoreflect.ListRange(bl, 0, 1)[0].Bytes()[0] = 'x'
+	if string(m.RepeatedBytes[0]) != "a" {
+		t.Errorf("modifying bytes returned by ListRange changed the list to %q", m.RepeatedBytes)
+	}
+}
+
+func TestListRangePanics(t *testing.T) {
+	m := &testpb.TestAllTypes{RepeatedInt32: []int32{0, 1, 2}}
+	l := m.ProtoReflect().Get(m.ProtoReflect().Descriptor().Fields().ByName("repeated_int32")).List()
+	for _, r := range [][2]int{{-1, 2}, {2, 1}, {0, 4}, {4, 4}} {
+		mustPanic(t, fmt.Sprintf("ListRange(l, %d, %d)", r[0], r[1]), func() {
+			protoreflect.ListRange(l, r[0], r[1])
+		})
+	}
+}
+
+func TestAppendTyped(t *testing.T) {
+	m := &testpb.TestAllTypes{RepeatedInt64: []int64{1}}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	mutable := func(name protoreflect.Name) protoreflect.List {
+		return m.ProtoReflect().Mutable(fields.ByName(name)).List()
+	}
+
+	protoreflect.AppendInt64s(mutable("repeated_int64"), []int64{2, math.MinInt64})
+	protoreflect.AppendInt64s(mutable("repeated_sfixed64"), []int64{-1})
+	protoreflect.AppendStrings(mutable("repeated_string"), []string{"a", "", "b"})
+	b := []byte("x")
+	protoreflect.AppendBytesList(mutable("repeated_bytes"), [][]byte{b, nil})
+	protoreflect.AppendStrings(mutable("repeated_string"), nil)
+
+	want := &testpb.TestAllTypes{
+		RepeatedInt64:    []int64{1, 2, math.MinInt64},
+		RepeatedSfixed64: []int64{-1},
+		RepeatedString:   []string{"a", "", "b"},
+		RepeatedBytes:    [][]byte{[]byte("x"), {}},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("typed appends produced %v, want %v", m, want)
+	}
+}
+
+func TestAppendTypedMismatch(t *testing.T) {
+	m := &testpb.TestAllTypes{}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	mutable := func(name protoreflect.Name) protoreflect.List {
+		return m.ProtoReflect().Mutable(fields.ByName(name)).List()
+	}
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"AppendInt64s on int32 list", func() { protoreflect.AppendInt64s(mutable("repeated_int32"), []int64{1}) }},
+		{"AppendInt64s on uint64 list", func() { protoreflect.AppendInt64s(mutable("repeated_uint64"), nil) }},
+		{"AppendStrings on bytes list", func() { protoreflect.AppendStrings(mutable("repeated_bytes"), []string{"a"}) }},
+		{"AppendBytesList on string list", func() { protoreflect.AppendBytesList(mutable("repeated_string"), [][]byte{nil}) }},
+		{"AppendStrings on message list", func() { protoreflect.AppendStrings(mutable("repeated_nested_message"), nil) }},
+	}
+	for _, tt := range tests {
+		mustPanic(t, tt.name, tt.f)
+	}
+	if n := proto.Size(m); n != 0 {
+		t.Errorf("mismatched appends modified the message to %v", m)
+	}
+}
+
+func TestSetTyped(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt64:  []int64{1, 2, 3},
+		RepeatedString: []string{"old"},
+		RepeatedBytes:  [][]byte{[]byte("old")},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	mutable := func(name protoreflect.Name) protoreflect.List {
+		return m.ProtoReflect().Mutable(fields.ByName(name)).List()
+	}
+
+	protoreflect.SetInt64s(mutable("repeated_int64"), []int64{4, 5})
+	protoreflect.SetStrings(mutable("repeated_string"), nil)
+	protoreflect.SetBytesList(mutable("repeated_bytes"), [][]byte{[]byte("a"), []byte("b")})
+	protoreflect.SetStrings(mutable("repeated_string"), []string{"new"})
+
+	want := &testpb.TestAllTypes{
+		RepeatedInt64:  []int64{4, 5},
+		RepeatedString: []string{"new"},
+		RepeatedBytes:  [][]byte{[]byte("a"), []byte("b")},
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("typed sets produced %v, want %v", m, want)
+	}
+}
+
+func TestSetTypedMismatch(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32:  []int32{1, 2},
+		RepeatedString: []string{"a"},
+		RepeatedBytes:  [][]byte{[]byte("b")},
+	}
+	orig := proto.Clone(m)
+	fields := m.ProtoReflect().Descriptor().Fields()
+	mutable := func(name protoreflect.Name) protoreflect.List {
+		return m.ProtoReflect().Mutable(fields.ByName(name)).List()
+	}
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"SetInt64s on int32 list", func() { protoreflect.SetInt64s(mutable("repeated_int32"), []int64{1}) }},
+		{"SetStrings on bytes list", func() { protoreflect.SetStrings(mutable("repeated_bytes"), []string{"a"}) }},
+		{"SetBytesList on string list", func() { protoreflect.SetBytesList(mutable("repeated_string"), nil) }},
+	}
+	for _, tt := range tests {
+		mustPanic(t, tt.name, tt.f)
+	}
+	if !proto.Equal(m, orig) {
+		t.Errorf("mismatched sets modified the message to %v, want %v", m, orig)
+	}
+}
+
+func TestInt64s(t *testing.T) {
+	fields := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields()
+	m := &testpb.TestAllTypes{
+		RepeatedInt64:    []int64{1, -2, math.MaxInt64},
+		RepeatedSint64:   []int64{-3},
+		RepeatedSfixed64: []int64{math.MinInt64},
+	}
+	for _, tt := range []struct {
+		name protoreflect.Name
+		want []int64
+	}{
+		{"repeated_int64", []int64{1, -2, math.MaxInt64}},
+		{"repeated_sint64", []int64{-3}},
+		{"repeated_sfixed64", []int64{math.MinInt64}},
+	} {
+		l := m.ProtoReflect().Get(fields.ByName(tt.name)).List()
+		if got := protoreflect.Int64s(l); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Int64s(%v) = %v, want %v", tt.name, got, tt.want)
+		}
+		for i, want := range tt.want {
+			if got := protoreflect.Int64At(l, i); got != want {
+				t.Errorf("Int64At(%v, %d) = %v, want %v", tt.name, i, got, want)
+			}
+		}
+	}
+
+	// The snapshot returned by Int64s does not alias the list,
+	// while Int64At observes changes to the list.
+	l := m.ProtoReflect().Mutable(fields.ByName("repeated_int64")).List()
+	got := protoreflect.Int64s(l)
+	got[0] = 100
+	l.Set(1, protoreflect.ValueOfInt64(200))
+	if m.RepeatedInt64[0] != 1 {
+		t.Errorf("modifying the result of Int64s changed the list to %v", m.RepeatedInt64)
+	}
+	if got := protoreflect.Int64At(l, 1); got != 200 {
+		t.Errorf("Int64At after Set = %v, want 200", got)
+	}
+	if got := protoreflect.Int64s((&testpb.TestAllTypes{}).ProtoReflect().Get(fields.ByName("repeated_int64")).List()); len(got) != 0 {
+		t.Errorf("Int64s on an empty list = %v, want []", got)
+	}
+
+	// Lists without a direct representation of their elements are read through Get.
+	dyn := dynamicpb.NewMessage(fields.ByName("repeated_int64").ContainingMessage()).Mutable(fields.ByName("repeated_int64")).List()
+	dyn.Append(protoreflect.ValueOfInt64(1))
+	dyn.Append(protoreflect.ValueOfInt64(200))
+	if got := protoreflect.Int64s(dyn); !reflect.DeepEqual(got, []int64{1, 200}) {
+		t.Errorf("Int64s(%T) = %v, want [1 200]", dyn, got)
+	}
+	if got := protoreflect.Int64At(dyn, 1); got != 200 {
+		t.Errorf("Int64At(%T, 1) = %v, want 200", dyn, got)
+	}
+
+	m = &testpb.TestAllTypes{
+		RepeatedInt32:  []int32{1},
+		RepeatedUint64: []uint64{1},
+	}
+	for _, tt := range []struct {
+		name string
+		f    func()
+	}{
+		{"Int64s on int32 list", func() { protoreflect.Int64s(m.ProtoReflect().Get(fields.ByName("repeated_int32")).List()) }},
+		{"Int64s on uint64 list", func() { protoreflect.Int64s(m.ProtoReflect().Get(fields.ByName("repeated_uint64")).List()) }},
+		{"Int64At on int32 list", func() { protoreflect.Int64At(m.ProtoReflect().Get(fields.ByName("repeated_int32")).List(), 0) }},
+		{"Int64At on uint64 list", func() { protoreflect.Int64At(m.ProtoReflect().Get(fields.ByName("repeated_uint64")).List(), 0) }},
+		{"Int64At out of range", func() { protoreflect.Int64At(m.ProtoReflect().Get(fields.ByName("repeated_int64")).List(), 0) }},
+	} {
+		mustPanic(t, tt.name, tt.f)
+	}
+}
+
+func BenchmarkInt64List(b *testing.B) {
+	const n = 1000
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("repeated_int64")
+	for _, m := range []struct {
+		name string
+		m    protoreflect.Message
+	}{
+		{"Generated", (&testpb.TestAllTypes{}).ProtoReflect()},
+		{"Dynamic", dynamicpb.NewMessage(fd.ContainingMessage())},
+	} {
+		l := m.m.Mutable(fd).List()
+		for i := 0; i < n; i++ {
+			l.Append(protoreflect.ValueOfInt64(int64(i)))
+		}
+
+		var sink int64
+		b.Run(m.name+"/Get", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					sink += l.Get(j).Int()
+				}
+			}
+		})
+		b.Run(m.name+"/Int64At", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					sink += protoreflect.Int64At(l, j)
+				}
+			}
+		})
+		b.Run(m.name+"/Int64s", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, v := range protoreflect.Int64s(l) {
+					sink += v
+				}
+			}
+		})
+		_ = sink
+	}
+}
+
+func TestReverseList(t *testing.T) {
+	for _, in := range [][]int32{nil, {1}, {1, 2}, {1, 2, 3}, {1, 2, 3, 4}} {
+		m := &testpb.TestAllTypes{RepeatedInt32: append([]int32(nil), in...)}
+		protoreflect.ReverseList(m.ProtoReflect().Mutable(m.ProtoReflect().Descriptor().Fields().ByName("repeated_int32")).List())
+		var want []int32
+		for i := len(in) - 1; i >= 0; i-- {
+			want = append(want, in[i])
+		}
+		if !reflect.DeepEqual(m.RepeatedInt32, want) {
+			t.Errorf("ReverseList(%v) = %v, want %v", in, m.RepeatedInt32, want)
+		}
+	}
+
+	// Message and bytes elements are moved, not copied.
+	m0, m1, m2 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(0)}, &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}, &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+	b0, b1 := []byte("b0"), []byte("b1")
+	m := &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{m0, m1, m2},
+		RepeatedBytes:         [][]byte{b0, b1},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	protoreflect.ReverseList(m.ProtoReflect().Mutable(fields.ByName("repeated_nested_message")).List())
+	protoreflect.ReverseList(m.ProtoReflect().Mutable(fields.ByName("repeated_bytes")).List())
+	if got := m.RepeatedNestedMessage; got[0] != m2 || got[1] != m1 || got[2] != m0 {
+		t.Errorf("ReverseList on messages produced %v, want the original messages in reverse order", got)
+	}
+	if got := m.RepeatedBytes; &got[0][0] != &b1[0] || &got[1][0] != &b0[0] {
+		t.Errorf("ReverseList on bytes produced %q, want the original slices in reverse order", got)
+	}
+}
+
+func TestIsPackable(t *testing.T) {
+	m := (&testpb.TestAllTypes{}).ProtoReflect()
+	for _, fd := range []protoreflect.Name{
+		"repeated_int32", "repeated_int64", "repeated_uint32", "repeated_uint64",
+		"repeated_sint32", "repeated_sint64", "repeated_fixed32", "repeated_fixed64",
+		"repeated_sfixed32", "repeated_sfixed64", "repeated_float", "repeated_double",
+		"repeated_bool", "repeated_nested_enum",
+	} {
+		if !protoreflect.IsPackable(m.Get(m.Descriptor().Fields().ByName(fd)).List()) {
+			t.Errorf("IsPackable(%v) = false, want true", fd)
+		}
+	}
+	for _, fd := range []protoreflect.Name{
+		"repeated_string", "repeated_bytes", "repeated_nested_message", "repeatedgroup",
+	} {
+		if protoreflect.IsPackable(m.Get(m.Descriptor().Fields().ByName(fd)).List()) {
+			t.Errorf("IsPackable(%v) = true, want false", fd)
+		}
+	}
+}
+
+func TestGrowList(t *testing.T) {
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("repeated_int32")
+	for _, m := range []protoreflect.Message{
+		(&testpb.TestAllTypes{}).ProtoReflect(),
+		dynamicpb.NewMessage(fd.ContainingMessage()),
+	} {
+		l := m.Mutable(fd).List()
+		l.Append(protoreflect.ValueOfInt32(1))
+		protoreflect.GrowList(l, 100)
+		if l.Len() != 1 || l.Get(0).Int() != 1 {
+			t.Errorf("%T: after GrowList, list has length %d, want [1]", m, l.Len())
+		}
+
+		// Elements appended after growing the list are visible through the message.
+		l.Append(protoreflect.ValueOfInt32(2))
+		got := m.Get(fd).List()
+		if got.Len() != 2 || got.Get(0).Int() != 1 || got.Get(1).Int() != 2 {
+			t.Errorf("%T: after GrowList and Append, message has %d elements, want [1 2]", m, got.Len())
+		}
+	}
+
+	// Growing never shrinks or reallocates a list with enough capacity.
+	pb := &testpb.TestAllTypes{RepeatedInt32: make([]int32, 1, 10)}
+	protoreflect.GrowList(pb.ProtoReflect().Mutable(fd).List(), 5)
+	if len(pb.RepeatedInt32) != 1 || cap(pb.RepeatedInt32) != 10 {
+		t.Errorf("GrowList(5) on list of length 1 and capacity 10 produced length %d and capacity %d", len(pb.RepeatedInt32), cap(pb.RepeatedInt32))
+	}
+
+	// Read-only lists ignore the hint.
+	m := (&testpb.TestAllTypes{}).ProtoReflect()
+	protoreflect.GrowList(m.Get(fd).List(), 10)
+	if m.Has(fd) {
+		t.Errorf("GrowList on a read-only list populated the field")
+	}
+}
+
+func BenchmarkGrowList(b *testing.B) {
+	const n = 1000
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("repeated_int32")
+	for _, bb := range []struct {
+		name string
+		new  func() protoreflect.Message
+	}{
+		{"Generated", func() protoreflect.Message { return (&testpb.TestAllTypes{}).ProtoReflect() }},
+		{"Dynamic", func() protoreflect.Message { return dynamicpb.NewMessage(fd.ContainingMessage()) }},
+	} {
+		for _, grow := range []bool{false, true} {
+			name := bb.name + "/NoGrow"
+			if grow {
+				name = bb.name + "/Grow"
+			}
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					l := bb.new().Mutable(fd).List()
+					if grow {
+						protoreflect.GrowList(l, n)
+					}
+					for j := int32(0); j < n; j++ {
+						l.Append(protoreflect.ValueOfInt32(j))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestListIndex(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		RepeatedInt32:  []int32{5, 7, 5},
+		RepeatedString: []string{"a", "b"},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(1)},
+			{A: proto.Int32(2)},
+		},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	list := func(name protoreflect.Name) protoreflect.List {
+		return m.ProtoReflect().Get(fields.ByName(name)).List()
+	}
+	nested := func(a int32) protoreflect.Value {
+		return protoreflect.ValueOfMessage((&testpb.TestAllTypes_NestedMessage{A: proto.Int32(a)}).ProtoReflect())
+	}
+
+	tests := []struct {
+		list protoreflect.List
+		in   protoreflect.Value
+		want int
+	}{
+		{list("repeated_int32"), protoreflect.ValueOfInt32(5), 0},
+		{list("repeated_int32"), protoreflect.ValueOfInt32(7), 1},
+		{list("repeated_int32"), protoreflect.ValueOfInt32(6), -1},
+		{list("repeated_int32"), protoreflect.ValueOfInt64(5), -1},
+		{list("repeated_string"), protoreflect.ValueOfString("b"), 1},
+		{list("repeated_string"), protoreflect.ValueOfString("c"), -1},
+		{list("repeated_nested_message"), nested(1), 0},
+		{list("repeated_nested_message"), nested(2), 1},
+		{list("repeated_nested_message"), nested(3), -1},
+		{list("repeated_int64"), protoreflect.ValueOfInt64(0), -1},
+	}
+	for _, tt := range tests {
+		if got := protoreflect.ListIndex(tt.list, tt.in); got != tt.want {
+			t.Errorf("ListIndex(%v, %v) = %d, want %d", tt.list, tt.in, got, tt.want)
+		}
+	}
+}
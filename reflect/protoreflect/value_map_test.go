@@ -0,0 +1,261 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestMapKeys(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		MapBoolBool:       map[bool]bool{true: true, false: false},
+		MapInt32Int32:     map[int32]int32{3: 0, -1: 0, 0: 0, math.MinInt32: 0, math.MaxInt32: 0},
+		MapInt64Int64:     map[int64]int64{10: 0, -10: 0, math.MaxInt64: 0},
+		MapUint32Uint32:   map[uint32]uint32{math.MaxUint32: 0, 0: 0, 7: 0},
+		MapUint64Uint64:   map[uint64]uint64{math.MaxUint64: 0, 1: 0},
+		MapSint32Sint32:   map[int32]int32{2: 0, -2: 0},
+		MapFixed64Fixed64: map[uint64]uint64{5: 0, 4: 0},
+		MapStringString:   map[string]string{"b": "", "": "", "a": "", "ab": "", "é": "", "Z": ""},
+	}
+	tests := []struct {
+		field string
+		want  []interface{}
+	}{
+		{"map_bool_bool", []interface{}{false, true}},
+		{"map_int32_int32", []interface{}{int32(math.MinInt32), int32(-1), int32(0), int32(3), int32(math.MaxInt32)}},
+		{"map_int64_int64", []interface{}{int64(-10), int64(10), int64(math.MaxInt64)}},
+		{"map_uint32_uint32", []interface{}{uint32(0), uint32(7), uint32(math.MaxUint32)}},
+		{"map_uint64_uint64", []interface{}{uint64(1), uint64(math.MaxUint64)}},
+		{"map_sint32_sint32", []interface{}{int32(-2), int32(2)}},
+		{"map_fixed64_fixed64", []interface{}{uint64(4), uint64(5)}},
+		{"map_string_string", []interface{}{"", "Z", "a", "ab", "b", "é"}},
+		{"map_string_nested_message", nil},
+	}
+	for _, tt := range tests {
+		fd := m.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(tt.field))
+		var got []interface{}
+		for _, k := range protoreflect.MapKeys(m.ProtoReflect().Get(fd).Map()) {
+			got = append(got, k.Interface())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MapKeys(%v) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestRangeTypedKeys(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		MapStringString: map[string]string{"a": "1", "b": "2"},
+		MapInt64Int64:   map[int64]int64{-1: 1, math.MaxInt64: 2},
+		MapInt32Int32:   map[int32]int32{1: 1},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	get := func(name protoreflect.Name) protoreflect.Map {
+		return m.ProtoReflect().Get(fields.ByName(name)).Map()
+	}
+
+	gotStrings := make(map[string]string)
+	protoreflect.RangeStringKeys(get("map_string_string"), func(k string, v protoreflect.Value) bool {
+		gotStrings[k] = v.String()
+		return true
+	})
+	if !reflect.DeepEqual(gotStrings, m.MapStringString) {
+		t.Errorf("RangeStringKeys visited %v, want %v", gotStrings, m.MapStringString)
+	}
+
+	gotInts := make(map[int64]int64)
+	protoreflect.RangeInt64Keys(get("map_int64_int64"), func(k int64, v protoreflect.Value) bool {
+		gotInts[k] = v.Int()
+		return true
+	})
+	if !reflect.DeepEqual(gotInts, m.MapInt64Int64) {
+		t.Errorf("RangeInt64Keys visited %v, want %v", gotInts, m.MapInt64Int64)
+	}
+
+	n := 0
+	protoreflect.RangeStringKeys(get("map_string_string"), func(string, protoreflect.Value) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("RangeStringKeys called f %d times after it returned false, want 1", n)
+	}
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"RangeStringKeys on int64 keys", func() {
+			protoreflect.RangeStringKeys(get("map_int64_int64"), func(string, protoreflect.Value) bool { return true })
+		}},
+		{"RangeInt64Keys on string keys", func() {
+			protoreflect.RangeInt64Keys(get("map_string_string"), func(int64, protoreflect.Value) bool { return true })
+		}},
+		{"RangeInt64Keys on int32 keys", func() {
+			protoreflect.RangeInt64Keys(get("map_int32_int32"), func(int64, protoreflect.Value) bool { return true })
+		}},
+		{"RangeStringKeys on empty map of uint64 keys", func() {
+			protoreflect.RangeStringKeys(get("map_uint64_uint64"), func(string, protoreflect.Value) bool { return true })
+		}},
+		{"RangeInt64Keys on empty map of string keys", func() {
+			protoreflect.RangeInt64Keys(get("map_string_bytes"), func(int64, protoreflect.Value) bool { return true })
+		}},
+		{"RangeInt64Keys on empty dynamic map of int32 keys", func() {
+			fd := fields.ByName("map_int32_int32")
+			protoreflect.RangeInt64Keys(dynamicpb.NewMessage(fd.ContainingMessage()).Get(fd).Map(), func(int64, protoreflect.Value) bool { return true })
+		}},
+	}
+	for _, tt := range tests {
+		mustPanic(t, tt.name, tt.f)
+	}
+}
+
+func TestGrowMap(t *testing.T) {
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("map_int32_int32")
+	for _, m := range []protoreflect.Message{
+		(&testpb.TestAllTypes{}).ProtoReflect(),
+		dynamicpb.NewMessage(fd.ContainingMessage()),
+	} {
+		mm := m.Mutable(fd).Map()
+		mm.Set(protoreflect.ValueOfInt32(1).MapKey(), protoreflect.ValueOfInt32(10))
+		protoreflect.GrowMap(mm, 100)
+		if mm.Len() != 1 {
+			t.Errorf("%T: after GrowMap, Len() = %d, want 1", m, mm.Len())
+		}
+
+		// Entries set after growing the map are visible through the message.
+		mm.Set(protoreflect.ValueOfInt32(2).MapKey(), protoreflect.ValueOfInt32(20))
+		got := m.Get(fd).Map()
+		if got.Len() != 2 || got.Get(protoreflect.ValueOfInt32(1).MapKey()).Int() != 10 || got.Get(protoreflect.ValueOfInt32(2).MapKey()).Int() != 20 {
+			t.Errorf("%T: after GrowMap and Set, message has %v entries, want {1: 10, 2: 20}", m, got.Len())
+		}
+	}
+
+	// Only empty maps are replaced by a larger map;
+	// the entries of a populated map are never moved.
+	pb := &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 10}}
+	before := reflect.ValueOf(pb.MapInt32Int32).Pointer()
+	protoreflect.GrowMap(pb.ProtoReflect().Mutable(fd).Map(), 100)
+	if after := reflect.ValueOf(pb.MapInt32Int32).Pointer(); after != before {
+		t.Errorf("GrowMap on a populated map replaced the map")
+	}
+
+	// Read-only maps ignore the hint.
+	m := (&testpb.TestAllTypes{}).ProtoReflect()
+	protoreflect.GrowMap(m.Get(fd).Map(), 10)
+	if m.Has(fd) {
+		t.Errorf("GrowMap on a read-only map populated the field")
+	}
+}
+
+func BenchmarkGrowMap(b *testing.B) {
+	const n = 1000
+	fd := (&testpb.TestAllTypes{}).ProtoReflect().Descriptor().Fields().ByName("map_int32_int32")
+	for _, bb := range []struct {
+		name string
+		new  func() protoreflect.Message
+	}{
+		{"Generated", func() protoreflect.Message { return (&testpb.TestAllTypes{}).ProtoReflect() }},
+		{"Dynamic", func() protoreflect.Message { return dynamicpb.NewMessage(fd.ContainingMessage()) }},
+	} {
+		for _, grow := range []bool{false, true} {
+			name := bb.name + "/NoGrow"
+			if grow {
+				name = bb.name + "/Grow"
+			}
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					mm := bb.new().Mutable(fd).Map()
+					if grow {
+						protoreflect.GrowMap(mm, n)
+					}
+					for j := int32(0); j < n; j++ {
+						mm.Set(protoreflect.ValueOfInt32(j).MapKey(), protoreflect.ValueOfInt32(j))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMapGetOrDefault(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		MapStringString: map[string]string{"present": "value", "empty": ""},
+	}
+	mm := m.ProtoReflect().Get(m.ProtoReflect().Descriptor().Fields().ByName("map_string_string")).Map()
+	def := protoreflect.ValueOfString("default")
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"present", "value"},
+		{"empty", ""},
+		{"absent", "default"},
+	}
+	for _, tt := range tests {
+		k := protoreflect.ValueOfString(tt.key).MapKey()
+		if got := protoreflect.MapGetOrDefault(mm, k, def); got.String() != tt.want {
+			t.Errorf("MapGetOrDefault(m, %q, %v) = %v, want %q", tt.key, def, got, tt.want)
+		}
+	}
+
+	// An invalid default is returned as is.
+	if got := protoreflect.MapGetOrDefault(mm, protoreflect.ValueOfString("absent").MapKey(), protoreflect.Value{}); got.IsValid() {
+		t.Errorf("MapGetOrDefault with an invalid default = %v, want invalid", got)
+	}
+}
+
+func TestMapGetTyped(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		MapInt64Int64:   map[int64]int64{1: -5, 2: 0},
+		MapStringString: map[string]string{"a": "x", "b": ""},
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	get := func(name protoreflect.Name) protoreflect.Map {
+		return m.ProtoReflect().Get(fields.ByName(name)).Map()
+	}
+	int64Key := func(k int64) protoreflect.MapKey { return protoreflect.ValueOfInt64(k).MapKey() }
+	stringKey := func(k string) protoreflect.MapKey { return protoreflect.ValueOfString(k).MapKey() }
+
+	for _, tt := range []struct {
+		key    int64
+		want   int64
+		wantOK bool
+	}{{1, -5, true}, {2, 0, true}, {3, 0, false}} {
+		if got, ok := protoreflect.MapGetInt64(get("map_int64_int64"), int64Key(tt.key)); got != tt.want || ok != tt.wantOK {
+			t.Errorf("MapGetInt64(m, %d) = %d, %v, want %d, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	for _, tt := range []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{{"a", "x", true}, {"b", "", true}, {"c", "", false}} {
+		if got, ok := protoreflect.MapGetString(get("map_string_string"), stringKey(tt.key)); got != tt.want || ok != tt.wantOK {
+			t.Errorf("MapGetString(m, %q) = %q, %v, want %q, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"MapGetInt64 on int32 values", func() { protoreflect.MapGetInt64(get("map_int32_int32"), protoreflect.ValueOfInt32(1).MapKey()) }},
+		{"MapGetInt64 on string values", func() { protoreflect.MapGetInt64(get("map_string_string"), stringKey("a")) }},
+		{"MapGetString on bytes values", func() { protoreflect.MapGetString(get("map_string_bytes"), stringKey("absent")) }},
+		{"MapGetString on message values", func() { protoreflect.MapGetString(get("map_string_nested_message"), stringKey("absent")) }},
+	}
+	for _, tt := range tests {
+		mustPanic(t, tt.name, tt.f)
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"bytes"
 	"math"
 	"reflect"
+	"strings"
 	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 var (
@@ -142,6 +145,145 @@ func TestValueEqual(t *testing.T) {
 	}
 }
 
+func TestValuePredicates(t *testing.T) {
+	const (
+		null = iota
+		scalar
+		message
+		list
+		mapKind
+	)
+	tests := []struct {
+		in   Value
+		want int
+	}{
+		{Value{}, null},
+		{ValueOf(nil), null},
+		{ValueOfBool(true), scalar},
+		{ValueOfInt32(1), scalar},
+		{ValueOfInt64(1), scalar},
+		{ValueOfUint32(1), scalar},
+		{ValueOfUint64(1), scalar},
+		{ValueOfFloat32(1), scalar},
+		{ValueOfFloat64(1), scalar},
+		{ValueOfString("hello"), scalar},
+		{ValueOfString(""), scalar},
+		{ValueOfBytes(nil), scalar},
+		{ValueOfEnum(1), scalar},
+		{ValueOfMessage(fakeMessage), message},
+		{ValueOfList(fakeList), list},
+		{ValueOfMap(fakeMap), mapKind},
+	}
+	for _, tt := range tests {
+		if got, want := !tt.in.IsValid(), tt.want == null; got != want {
+			t.Errorf("!Value(%v).IsValid() = %v, want %v", tt.in, got, want)
+		}
+		if got, want := tt.in.IsScalar(), tt.want == scalar; got != want {
+			t.Errorf("Value(%v).IsScalar() = %v, want %v", tt.in, got, want)
+		}
+		if got, want := tt.in.IsMessage(), tt.want == message; got != want {
+			t.Errorf("Value(%v).IsMessage() = %v, want %v", tt.in, got, want)
+		}
+		if got, want := tt.in.IsList(), tt.want == list; got != want {
+			t.Errorf("Value(%v).IsList() = %v, want %v", tt.in, got, want)
+		}
+		if got, want := tt.in.IsMap(), tt.want == mapKind; got != want {
+			t.Errorf("Value(%v).IsMap() = %v, want %v", tt.in, got, want)
+		}
+	}
+}
+
+func TestValueIsZero(t *testing.T) {
+	tests := []struct {
+		in   Value
+		want bool
+	}{
+		{Value{}, false},
+		{ValueOfBool(false), true},
+		{ValueOfBool(true), false},
+		{ValueOfInt32(0), true},
+		{ValueOfInt32(-1), false},
+		{ValueOfInt64(0), true},
+		{ValueOfInt64(1), false},
+		{ValueOfUint32(0), true},
+		{ValueOfUint32(1), false},
+		{ValueOfUint64(0), true},
+		{ValueOfUint64(1), false},
+		{ValueOfFloat32(0), true},
+		{ValueOfFloat32(float32(math.Copysign(0, -1))), false},
+		{ValueOfFloat32(1.5), false},
+		{ValueOfFloat64(0), true},
+		{ValueOfFloat64(math.Copysign(0, -1)), false},
+		{ValueOfFloat64(math.NaN()), false},
+		{ValueOfString(""), true},
+		{ValueOfString("a"), false},
+		{ValueOfBytes(nil), true},
+		{ValueOfBytes([]byte{}), true},
+		{ValueOfBytes([]byte{0}), false},
+		{ValueOfEnum(0), true},
+		{ValueOfEnum(1), false},
+		{ValueOfMessage(fakeMessage), false},
+		{ValueOfList(fakeList), false},
+		{ValueOfMap(fakeMap), false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsZero(); got != tt.want {
+			t.Errorf("Value(%v).IsZero() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMapKeyCanonicalString(t *testing.T) {
+	tests := []struct {
+		in   MapKey
+		want string
+	}{
+		{ValueOfBool(true).MapKey(), "b:true"},
+		{ValueOfBool(false).MapKey(), "b:false"},
+		{ValueOfInt32(-42).MapKey(), "i32:-42"},
+		{ValueOfInt64(42).MapKey(), "i64:42"},
+		{ValueOfUint32(42).MapKey(), "u32:42"},
+		{ValueOfUint64(math.MaxUint64).MapKey(), "u64:18446744073709551615"},
+		{ValueOfString("hello").MapKey(), "s:hello"},
+		{ValueOfString("").MapKey(), "s:"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.CanonicalString(); got != tt.want {
+			t.Errorf("MapKey(%v).CanonicalString() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Keys of different types holding the same number must not collide.
+	numeric := []MapKey{
+		ValueOfInt32(1).MapKey(),
+		ValueOfInt64(1).MapKey(),
+		ValueOfUint32(1).MapKey(),
+		ValueOfUint64(1).MapKey(),
+		ValueOfString("1").MapKey(),
+	}
+	seen := make(map[string]bool)
+	for _, k := range numeric {
+		s := k.CanonicalString()
+		if seen[s] {
+			t.Errorf("MapKey(%v).CanonicalString() = %q collides with a key of another type", k, s)
+		}
+		seen[s] = true
+	}
+}
+
+// identityMessage is a message that panics if its fields are traversed.
+type identityMessage struct{ Message }
+
+func (m *identityMessage) Interface() ProtoMessage { return m }
+func (m *identityMessage) ProtoReflect() Message   { return m }
+
+func TestValueEqualIdentity(t *testing.T) {
+	m := new(identityMessage)
+	if !ValueOfMessage(m).Equal(ValueOfMessage(m)) {
+		t.Errorf("ValueOfMessage(m).Equal(ValueOfMessage(m)) = false, want true")
+	}
+}
+
 func BenchmarkValue(b *testing.B) {
 	const testdata = "The quick brown fox jumped over the lazy dog."
 	var sink1 string
@@ -189,3 +331,682 @@ func BenchmarkValue(b *testing.B) {
 
 	_, _, _ = sink1, sink2, sink3
 }
+
+func TestRawFieldsIsValid(t *testing.T) {
+	tag := func(b RawFields, num FieldNumber, typ protowire.Type) RawFields {
+		return protowire.AppendTag(b, num, typ)
+	}
+	varint := func(b RawFields, num FieldNumber) RawFields {
+		return protowire.AppendVarint(tag(b, num, protowire.VarintType), 1)
+	}
+
+	tests := []struct {
+		name string
+		in   RawFields
+		want bool
+	}{
+		{"empty", nil, true},
+		{"balanced group", tag(varint(tag(nil, 1, protowire.StartGroupType), 2), 1, protowire.EndGroupType), true},
+		{"nested groups", tag(tag(tag(tag(nil, 1, protowire.StartGroupType), 2, protowire.StartGroupType), 2, protowire.EndGroupType), 1, protowire.EndGroupType), true},
+		{"unterminated group", varint(tag(nil, 1, protowire.StartGroupType), 2), false},
+		{"stray end group", tag(varint(nil, 1), 1, protowire.EndGroupType), false},
+		{"mismatched end group", tag(tag(nil, 1, protowire.StartGroupType), 2, protowire.EndGroupType), false},
+		{"cross-nested groups", tag(tag(tag(tag(nil, 1, protowire.StartGroupType), 2, protowire.StartGroupType), 1, protowire.EndGroupType), 2, protowire.EndGroupType), false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsValid(); got != tt.want {
+			t.Errorf("%v: RawFields(%x).IsValid() = %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRawFieldsListSorted(t *testing.T) {
+	var b RawFields
+	for _, num := range []FieldNumber{30, 2, 1000, 2, 17} {
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(num))
+	}
+	got := b.ListSorted()
+	want := []FieldNumber{2, 17, 30, 1000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RawFields.ListSorted() = %v, want %v", got, want)
+	}
+	if got := RawFields(nil).ListSorted(); len(got) != 0 {
+		t.Errorf("RawFields(nil).ListSorted() = %v, want empty", got)
+	}
+}
+
+func TestRawFieldsRangeSorted(t *testing.T) {
+	var b RawFields
+	for i, num := range []FieldNumber{30, 2, 1000, 2, 17, 30} {
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(i))
+	}
+	field := func(num FieldNumber, vs ...uint64) RawFields {
+		var b RawFields
+		for _, v := range vs {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		}
+		return b
+	}
+
+	var gotNums []FieldNumber
+	var gotFields []RawFields
+	b.RangeSorted(func(num FieldNumber, raw RawFields) bool {
+		gotNums = append(gotNums, num)
+		gotFields = append(gotFields, raw)
+		return true
+	})
+	wantNums := []FieldNumber{2, 17, 30, 1000}
+	wantFields := []RawFields{field(2, 1, 3), field(17, 4), field(30, 0, 5), field(1000, 2)}
+	if !reflect.DeepEqual(gotNums, wantNums) || !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("RawFields.RangeSorted visited %v with fields %x, want %v with fields %x", gotNums, gotFields, wantNums, wantFields)
+	}
+
+	n := 0
+	b.RangeSorted(func(FieldNumber, RawFields) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("RawFields.RangeSorted called f %d times after it returned false, want 1", n)
+	}
+	RawFields(nil).RangeSorted(func(num FieldNumber, _ RawFields) bool {
+		t.Errorf("RawFields(nil).RangeSorted called f with field %d", num)
+		return true
+	})
+}
+
+func TestRawFieldsSorted(t *testing.T) {
+	field := func(b RawFields, num FieldNumber, v string) RawFields {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, []byte(v))
+	}
+	var in RawFields
+	in = field(in, 3, "c1")
+	in = field(in, 1, "a1")
+	in = field(in, 3, "c2")
+	in = protowire.AppendTag(in, 2, protowire.StartGroupType)
+	in = field(in, 1, "nested")
+	in = protowire.AppendTag(in, 2, protowire.EndGroupType)
+	in = field(in, 1, "a2")
+	orig := append(RawFields(nil), in...)
+
+	var want RawFields
+	want = field(want, 1, "a1")
+	want = field(want, 1, "a2")
+	want = protowire.AppendTag(want, 2, protowire.StartGroupType)
+	want = field(want, 1, "nested")
+	want = protowire.AppendTag(want, 2, protowire.EndGroupType)
+	want = field(want, 3, "c1")
+	want = field(want, 3, "c2")
+
+	got := in.Sorted()
+	if !bytes.Equal(got, want) {
+		t.Errorf("RawFields.Sorted() = %x, want %x", got, want)
+	}
+	if !bytes.Equal(in, orig) {
+		t.Errorf("RawFields.Sorted() modified its input to %x, want %x", in, orig)
+	}
+	if got := want.Sorted(); !bytes.Equal(got, want) {
+		t.Errorf("RawFields.Sorted() on sorted input = %x, want %x", got, want)
+	}
+
+	malformed := in[:len(in)-1]
+	if got := malformed.Sorted(); !bytes.Equal(got, malformed) {
+		t.Errorf("RawFields.Sorted() on malformed input = %x, want %x", got, malformed)
+	}
+	if got := RawFields(nil).Sorted(); len(got) != 0 {
+		t.Errorf("RawFields(nil).Sorted() = %x, want empty", got)
+	}
+}
+
+func TestRawFieldsSubMessage(t *testing.T) {
+	var inner RawFields
+	inner = protowire.AppendTag(inner, 1, protowire.VarintType)
+	inner = protowire.AppendVarint(inner, 150)
+
+	var nested RawFields
+	nested = protowire.AppendTag(nested, 3, protowire.BytesType)
+	nested = protowire.AppendBytes(nested, inner)
+
+	var b RawFields
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, nested)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte("second"))
+	b = protowire.AppendTag(b, 4, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 4)
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, nil)
+
+	got, ok := b.SubMessage(2)
+	if !ok || !bytes.Equal(got, nested) {
+		t.Fatalf("RawFields.SubMessage(2) = %x, %v, want %x, true", got, ok, nested)
+	}
+	got, ok = got.SubMessage(3)
+	if !ok || !bytes.Equal(got, inner) {
+		t.Errorf("RawFields.SubMessage(2).SubMessage(3) = %x, %v, want %x, true", got, ok, inner)
+	}
+	if got, ok := b.SubMessage(5); !ok || len(got) != 0 {
+		t.Errorf("RawFields.SubMessage(5) = %x, %v, want empty, true", got, ok)
+	}
+
+	for _, num := range []FieldNumber{1, 4, 6} {
+		if got, ok := b.SubMessage(num); ok {
+			t.Errorf("RawFields.SubMessage(%d) = %x, true, want false", num, got)
+		}
+	}
+	if got, ok := b[:len(b)-1].SubMessage(5); ok {
+		t.Errorf("RawFields.SubMessage(5) on truncated input = %x, true, want false", got)
+	}
+}
+
+func TestRawFieldsAppendField(t *testing.T) {
+	varint := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 150)
+	str := protowire.AppendString(protowire.AppendTag(nil, 2, protowire.BytesType), "hello")
+	group := protowire.AppendTag(protowire.AppendTag(nil, 3, protowire.StartGroupType), 3, protowire.EndGroupType)
+
+	var b RawFields
+	for _, field := range [][]byte{varint, str, append(append([]byte(nil), varint...), group...), nil} {
+		var ok bool
+		if b, ok = b.AppendField(field); !ok {
+			t.Errorf("RawFields.AppendField(%x) reported false, want true", field)
+		}
+	}
+	want := RawFields(bytes.Join([][]byte{varint, str, varint, group}, nil))
+	if !bytes.Equal(b, want) {
+		t.Errorf("after valid appends, RawFields = %x, want %x", b, want)
+	}
+
+	for _, field := range [][]byte{
+		varint[:len(varint)-1],
+		str[:len(str)-1],
+		group[:1],
+		protowire.AppendTag(nil, 3, protowire.EndGroupType),
+		{0x80},
+	} {
+		got, ok := b.AppendField(field)
+		if ok || !bytes.Equal(got, want) {
+			t.Errorf("RawFields.AppendField(%x) = %x, %v, want %x, false", field, got, ok, want)
+		}
+	}
+	if !b.IsValid() {
+		t.Errorf("RawFields.IsValid() = false after AppendField, want true")
+	}
+}
+
+func TestRawFieldsStripField(t *testing.T) {
+	field := func(num FieldNumber, v string) []byte {
+		return protowire.AppendString(protowire.AppendTag(nil, num, protowire.BytesType), v)
+	}
+	join := func(fields ...[]byte) RawFields { return RawFields(bytes.Join(fields, nil)) }
+	in := join(field(1, "a"), field(2, "b"), field(1, "c"), field(3, "d"))
+	orig := append(RawFields(nil), in...)
+
+	tests := []struct {
+		in   RawFields
+		num  FieldNumber
+		want RawFields
+	}{
+		{in, 2, join(field(1, "a"), field(1, "c"), field(3, "d"))},
+		{in, 1, join(field(2, "b"), field(3, "d"))},
+		{in, 4, in},
+		{nil, 1, nil},
+		{join(field(1, "a"), field(2, "b"), []byte{0x80}), 1, join(field(2, "b"), []byte{0x80})},
+		{join(field(2, "b"), field(1, "a")[:2]), 1, join(field(2, "b"), field(1, "a")[:2])},
+	}
+	for _, tt := range tests {
+		if got := tt.in.StripField(tt.num); !bytes.Equal(got, tt.want) {
+			t.Errorf("RawFields(%x).StripField(%d) = %x, want %x", tt.in, tt.num, got, tt.want)
+		}
+	}
+	if !bytes.Equal(in, orig) {
+		t.Errorf("RawFields.StripField modified its input to %x, want %x", in, orig)
+	}
+}
+
+func TestRawFieldsDump(t *testing.T) {
+	var sub RawFields
+	sub = protowire.AppendTag(sub, 1, protowire.VarintType)
+	sub = protowire.AppendVarint(sub, 2)
+
+	var b RawFields
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 150)
+	b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, math.MaxUint32)
+	b = protowire.AppendTag(b, 3, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, 1<<40)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte{0xff})
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, sub)
+	b = protowire.AppendTag(b, 6, protowire.StartGroupType)
+	b = append(b, sub...)
+	b = protowire.AppendTag(b, 6, protowire.EndGroupType)
+	b = protowire.AppendTag(b, 7, protowire.BytesType)
+	b = protowire.AppendBytes(b, bytes.Repeat([]byte{0xff}, 20))
+	b = protowire.AppendTag(b, 8, protowire.BytesType)
+	b = protowire.AppendBytes(b, nil)
+
+	tests := []struct {
+		in   RawFields
+		want string
+	}{{
+		in:   nil,
+		want: "",
+	}, {
+		in: b,
+		want: "field=1 wire=varint value=150\n" +
+			"field=2 wire=fixed32 value=4294967295\n" +
+			"field=3 wire=fixed64 value=1099511627776\n" +
+			"field=4 wire=bytes value=len=1 ff\n" +
+			"field=5 wire=bytes value=len=2 0802 {\n" +
+			"  field=1 wire=varint value=2\n" +
+			"}\n" +
+			"field=6 wire=group value={\n" +
+			"  field=1 wire=varint value=2\n" +
+			"}\n" +
+			"field=7 wire=bytes value=len=20 ffffffffffffffffffffffffffffffff...\n" +
+			"field=8 wire=bytes value=len=0 \n",
+	}}
+	for _, tt := range tests {
+		if got := tt.in.Dump(); got != tt.want {
+			t.Errorf("RawFields(%x).Dump() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Malformed input is annotated rather than causing a panic.
+	// Only the prefix is checked since the error text is not stable.
+	malformed := []struct {
+		in         RawFields
+		wantPrefix string
+	}{{
+		in: append(sub[:len(sub):len(sub)], 0x80),
+		wantPrefix: "field=1 wire=varint value=2\n" +
+			"malformed tag at offset 2: ",
+	}, {
+		in: append(sub[:len(sub):len(sub)], protowire.AppendTag(nil, 2, protowire.BytesType)...),
+		wantPrefix: "field=1 wire=varint value=2\n" +
+			"field=2 wire=bytes malformed value at offset 3: ",
+	}, {
+		in:         protowire.AppendTag(nil, 9, protowire.EndGroupType),
+		wantPrefix: "field=9 wire=endgroup malformed value at offset 1: ",
+	}, {
+		in: append(protowire.AppendTag(nil, 6, protowire.StartGroupType), sub...),
+		wantPrefix: "field=6 wire=group value={\n" +
+			"  field=1 wire=varint value=2\n" +
+			"  malformed tag at offset 3: ",
+	}, {
+		in: protowire.AppendTag(protowire.AppendTag(nil, 6, protowire.StartGroupType), 7, protowire.EndGroupType),
+		wantPrefix: "field=6 wire=group value={\n" +
+			"  field=7 wire=endgroup malformed value at offset 2: ",
+	}}
+	for _, tt := range malformed {
+		if got := tt.in.Dump(); !strings.HasPrefix(got, tt.wantPrefix) || strings.Count(got, "\n") != strings.Count(tt.wantPrefix, "\n")+1 {
+			t.Errorf("RawFields(%x).Dump() = %q, want prefix %q", tt.in, got, tt.wantPrefix)
+		}
+	}
+
+	// Deeply nested messages and groups are rendered at every level.
+	const depth = 1000
+	var msg, grp RawFields
+	for i := 0; i < depth; i++ {
+		msg = protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), msg)
+		grp = protowire.AppendTag(append(protowire.AppendTag(nil, 1, protowire.StartGroupType), grp...), 1, protowire.EndGroupType)
+	}
+	// The innermost message is empty, so it is not rendered with braces.
+	for _, tt := range []struct {
+		in        RawFields
+		wantLevel int
+	}{{msg, depth - 1}, {grp, depth}} {
+		got := tt.in.Dump()
+		if n, m := strings.Count(got, "{\n"), strings.Count(got, "}\n"); n != tt.wantLevel || m != tt.wantLevel {
+			t.Errorf("RawFields.Dump() of %d nested fields opened %d and closed %d levels, want %d", depth, n, m, tt.wantLevel)
+		}
+	}
+}
+
+func TestRawFieldsCount(t *testing.T) {
+	var b RawFields
+	for _, num := range []FieldNumber{1, 2, 2, 30} {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, []byte("abc"))
+	}
+	tests := []struct {
+		in   RawFields
+		want int
+	}{
+		{nil, 0},
+		{RawFields{}, 0},
+		{b[:5], 1},
+		{b, 4},
+		{b[:len(b)-1], -1},
+		{RawFields{0x80}, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Count(); got != tt.want {
+			t.Errorf("RawFields(%x).Count() = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if n := testing.AllocsPerRun(10, func() { b.Count() }); n > 0 {
+		t.Errorf("RawFields.Count() allocated %v times, want 0", n)
+	}
+}
+
+func TestValueMapKeyPanic(t *testing.T) {
+	tests := []struct {
+		in   Value
+		want string
+	}{
+		{ValueOfFloat32(1), "cannot use float32 value as map key"},
+		{ValueOfFloat64(1), "cannot use float64 value as map key"},
+		{ValueOfBytes([]byte("k")), "cannot use bytes value as map key"},
+		{ValueOfEnum(1), "cannot use enum value as map key"},
+		{ValueOfMessage(fakeMessage), "cannot use message value as map key"},
+		{Value{}, "cannot use nil value as map key"},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				got, _ := recover().(string)
+				if !strings.Contains(got, tt.want) {
+					t.Errorf("Value(%v).MapKey() panicked with %q, want message containing %q", tt.in, got, tt.want)
+				}
+			}()
+			tt.in.MapKey()
+		}()
+	}
+}
+
+func TestRawFieldsAppendValue(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		v    Value
+	}{
+		{BoolKind, ValueOfBool(true)},
+		{EnumKind, ValueOfEnum(-2)},
+		{Int32Kind, ValueOfInt32(math.MinInt32)},
+		{Int64Kind, ValueOfInt64(math.MinInt64)},
+		{Uint32Kind, ValueOfUint32(math.MaxUint32)},
+		{Uint64Kind, ValueOfUint64(math.MaxUint64)},
+		{Sint32Kind, ValueOfInt32(-1)},
+		{Sint64Kind, ValueOfInt64(math.MinInt64)},
+		{Fixed32Kind, ValueOfUint32(math.MaxUint32)},
+		{Sfixed32Kind, ValueOfInt32(-32)},
+		{FloatKind, ValueOfFloat32(1.5)},
+		{Fixed64Kind, ValueOfUint64(math.MaxUint64)},
+		{Sfixed64Kind, ValueOfInt64(-64)},
+		{DoubleKind, ValueOfFloat64(math.Inf(-1))},
+		{StringKind, ValueOfString("hello")},
+		{BytesKind, ValueOfBytes([]byte{0, 1, 2})},
+	}
+	var b RawFields
+	for i, tt := range tests {
+		b = b.AppendValue(FieldNumber(i+1), tt.kind, tt.v)
+	}
+	if !b.IsValid() {
+		t.Fatalf("RawFields built with AppendValue is invalid: %x", b)
+	}
+	for i, tt := range tests {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 || num != FieldNumber(i+1) {
+			t.Fatalf("%v: ConsumeTag = %v, %v, %v, want field %v", tt.kind, num, typ, n, i+1)
+		}
+		b = b[n:]
+		var got Value
+		switch typ {
+		case protowire.VarintType:
+			x, n := protowire.ConsumeVarint(b)
+			b = b[n:]
+			switch tt.kind {
+			case BoolKind:
+				got = ValueOfBool(protowire.DecodeBool(x))
+			case EnumKind:
+				got = ValueOfEnum(EnumNumber(x))
+			case Int32Kind:
+				got = ValueOfInt32(int32(x))
+			case Int64Kind:
+				got = ValueOfInt64(int64(x))
+			case Uint32Kind:
+				got = ValueOfUint32(uint32(x))
+			case Uint64Kind:
+				got = ValueOfUint64(x)
+			case Sint32Kind:
+				got = ValueOfInt32(int32(protowire.DecodeZigZag(x & math.MaxUint32)))
+			case Sint64Kind:
+				got = ValueOfInt64(protowire.DecodeZigZag(x))
+			}
+		case protowire.Fixed32Type:
+			x, n := protowire.ConsumeFixed32(b)
+			b = b[n:]
+			switch tt.kind {
+			case Fixed32Kind:
+				got = ValueOfUint32(x)
+			case Sfixed32Kind:
+				got = ValueOfInt32(int32(x))
+			case FloatKind:
+				got = ValueOfFloat32(math.Float32frombits(x))
+			}
+		case protowire.Fixed64Type:
+			x, n := protowire.ConsumeFixed64(b)
+			b = b[n:]
+			switch tt.kind {
+			case Fixed64Kind:
+				got = ValueOfUint64(x)
+			case Sfixed64Kind:
+				got = ValueOfInt64(int64(x))
+			case DoubleKind:
+				got = ValueOfFloat64(math.Float64frombits(x))
+			}
+		case protowire.BytesType:
+			x, n := protowire.ConsumeBytes(b)
+			b = b[n:]
+			switch tt.kind {
+			case StringKind:
+				got = ValueOfString(string(x))
+			case BytesKind:
+				got = ValueOfBytes(x)
+			}
+		}
+		if !got.Equal(tt.v) {
+			t.Errorf("%v: round trip of %v through AppendValue = %v", tt.kind, tt.v, got)
+		}
+	}
+	if len(b) != 0 {
+		t.Errorf("%d trailing bytes after decoding every field", len(b))
+	}
+}
+
+func TestRawFieldsAppendValuePanics(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		v    Value
+	}{
+		{MessageKind, ValueOfMessage(fakeMessage)},
+		{GroupKind, ValueOfMessage(fakeMessage)},
+		{Kind(0), ValueOfInt32(0)},
+
+		// Values that would otherwise be silently converted to the type of kind.
+		{BoolKind, ValueOfInt32(1)},
+		{EnumKind, ValueOfInt32(1)},
+		{Int32Kind, ValueOfInt64(math.MaxInt64)},
+		{Int32Kind, ValueOfUint32(0)},
+		{Int64Kind, ValueOfInt32(1)},
+		{Int64Kind, ValueOfEnum(1)},
+		{Uint32Kind, ValueOfUint64(math.MaxUint64)},
+		{Uint64Kind, ValueOfUint32(1)},
+		{Sint32Kind, ValueOfInt64(math.MinInt64)},
+		{Sint64Kind, ValueOfInt32(-1)},
+		{Fixed32Kind, ValueOfUint64(math.MaxUint64)},
+		{Fixed64Kind, ValueOfUint32(1)},
+		{Sfixed32Kind, ValueOfInt64(math.MaxInt64)},
+		{Sfixed64Kind, ValueOfInt32(-1)},
+		{FloatKind, ValueOfFloat64(math.MaxFloat64)},
+		{DoubleKind, ValueOfFloat32(1)},
+		{StringKind, ValueOfBytes(nil)},
+		{BytesKind, ValueOfString("")},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("AppendValue(1, %v, %v) did not panic", tt.kind, tt.v)
+				}
+			}()
+			RawFields(nil).AppendValue(1, tt.kind, tt.v)
+		}()
+	}
+}
+
+func TestValueConvertTo(t *testing.T) {
+	tests := []struct {
+		in     Value
+		kind   Kind
+		want   Value
+		wantOK bool
+	}{
+		// Widening conversions.
+		{ValueOfInt32(-5), Int64Kind, ValueOfInt64(-5), true},
+		{ValueOfInt32(math.MinInt32), Sfixed64Kind, ValueOfInt64(math.MinInt32), true},
+		{ValueOfUint32(math.MaxUint32), Uint64Kind, ValueOfUint64(math.MaxUint32), true},
+		{ValueOfFloat32(1.5), DoubleKind, ValueOfFloat64(1.5), true},
+
+		// Narrowing conversions that fit.
+		{ValueOfInt64(math.MaxInt32), Int32Kind, ValueOfInt32(math.MaxInt32), true},
+		{ValueOfInt64(math.MinInt32), Sint32Kind, ValueOfInt32(math.MinInt32), true},
+		{ValueOfUint64(math.MaxUint32), Fixed32Kind, ValueOfUint32(math.MaxUint32), true},
+		{ValueOfFloat64(0.25), FloatKind, ValueOfFloat32(0.25), true},
+		{ValueOfFloat64(math.Inf(1)), FloatKind, ValueOfFloat32(float32(math.Inf(1))), true},
+		{ValueOfFloat64(math.NaN()), FloatKind, ValueOfFloat32(float32(math.NaN())), true},
+
+		// Narrowing conversions that do not fit.
+		{ValueOfInt64(math.MaxInt32 + 1), Int32Kind, Value{}, false},
+		{ValueOfInt64(math.MinInt32 - 1), Int32Kind, Value{}, false},
+		{ValueOfUint64(math.MaxUint32 + 1), Uint32Kind, Value{}, false},
+		{ValueOfFloat64(0.1), FloatKind, Value{}, false},
+		{ValueOfFloat64(math.MaxFloat64), FloatKind, Value{}, false},
+
+		// Identity conversions.
+		{ValueOfBool(true), BoolKind, ValueOfBool(true), true},
+		{ValueOfEnum(1), EnumKind, ValueOfEnum(1), true},
+		{ValueOfString("s"), StringKind, ValueOfString("s"), true},
+		{ValueOfBytes([]byte("b")), BytesKind, ValueOfBytes([]byte("b")), true},
+		{ValueOfInt64(1), Int64Kind, ValueOfInt64(1), true},
+
+		// Unsupported conversions.
+		{ValueOfInt32(1), Uint32Kind, Value{}, false},
+		{ValueOfUint64(1), Int64Kind, Value{}, false},
+		{ValueOfInt32(1), DoubleKind, Value{}, false},
+		{ValueOfInt32(1), EnumKind, Value{}, false},
+		{ValueOfString("s"), BytesKind, Value{}, false},
+		{ValueOfMessage(fakeMessage), MessageKind, Value{}, false},
+		{Value{}, Int32Kind, Value{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.in.ConvertTo(tt.kind)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("Value(%v).ConvertTo(%v) = %v, %v, want %v, %v", tt.in, tt.kind, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestValueTryAccessors(t *testing.T) {
+	values := []Value{
+		{},
+		ValueOfBool(true),
+		ValueOfInt32(-32),
+		ValueOfInt64(-64),
+		ValueOfUint32(32),
+		ValueOfUint64(64),
+		ValueOfFloat32(1.5),
+		ValueOfFloat64(2.5),
+		ValueOfString("s"),
+		ValueOfBytes([]byte("b")),
+		ValueOfEnum(1),
+		ValueOfMessage(fakeMessage),
+		ValueOfList(fakeList),
+		ValueOfMap(fakeMap),
+	}
+	for _, v := range values {
+		// Each Try accessor must succeed exactly when the panicking
+		// accessor does not panic, and agree with it on the result.
+		check := func(name string, tryGot interface{}, ok bool, get func() interface{}) {
+			var want interface{}
+			panicked := func() (panicked bool) {
+				defer func() { panicked = recover() != nil }()
+				want = get()
+				return false
+			}()
+			if ok == panicked {
+				t.Errorf("Value(%v).Try%s() reported %v, but the panicking accessor panicked = %v", v, name, ok, panicked)
+			}
+			if ok && !reflect.DeepEqual(tryGot, want) {
+				t.Errorf("Value(%v).Try%s() = %v, want %v", v, name, tryGot, want)
+			}
+		}
+		b, ok := v.TryBool()
+		check("Bool", b, ok, func() interface{} { return v.Bool() })
+		i, ok := v.TryInt()
+		check("Int", i, ok, func() interface{} { return v.Int() })
+		u, ok := v.TryUint()
+		check("Uint", u, ok, func() interface{} { return v.Uint() })
+		f, ok := v.TryFloat()
+		check("Float", f, ok, func() interface{} { return v.Float() })
+		bs, ok := v.TryBytes()
+		check("Bytes", bs, ok, func() interface{} { return v.Bytes() })
+
+		s, ok := v.TryString()
+		if wantOK := v.typ == stringType; ok != wantOK || (ok && s != v.String()) {
+			t.Errorf("Value(%v).TryString() = %q, %v, want %q, %v", v, s, ok, v.String(), wantOK)
+		}
+	}
+}
+
+func TestMapKeyCompare(t *testing.T) {
+	// Each group holds keys of one type in ascending order.
+	groups := [][]MapKey{
+		{ValueOfBool(false).MapKey(), ValueOfBool(true).MapKey()},
+		{ValueOfInt32(math.MinInt32).MapKey(), ValueOfInt32(-1).MapKey(), ValueOfInt32(0).MapKey(), ValueOfInt32(math.MaxInt32).MapKey()},
+		{ValueOfInt64(math.MinInt64).MapKey(), ValueOfInt64(0).MapKey(), ValueOfInt64(math.MaxInt64).MapKey()},
+		{ValueOfUint32(0).MapKey(), ValueOfUint32(1).MapKey(), ValueOfUint32(math.MaxUint32).MapKey()},
+		{ValueOfUint64(0).MapKey(), ValueOfUint64(1 << 63).MapKey(), ValueOfUint64(math.MaxUint64).MapKey()},
+		{ValueOfString("").MapKey(), ValueOfString("Z").MapKey(), ValueOfString("a").MapKey(), ValueOfString("ab").MapKey(), ValueOfString("é").MapKey()},
+	}
+	for _, keys := range groups {
+		for i, x := range keys {
+			for j, y := range keys {
+				if got, want := x.Less(y), i < j; got != want {
+					t.Errorf("MapKey(%v).Less(%v) = %v, want %v", x, y, got, want)
+				}
+				if got, want := x.Equal(y), i == j; got != want {
+					t.Errorf("MapKey(%v).Equal(%v) = %v, want %v", x, y, got, want)
+				}
+			}
+		}
+	}
+
+	mismatched := [][2]MapKey{
+		{ValueOfInt32(1).MapKey(), ValueOfInt64(1).MapKey()},
+		{ValueOfUint32(1).MapKey(), ValueOfInt32(1).MapKey()},
+		{ValueOfString("1").MapKey(), ValueOfUint64(1).MapKey()},
+		{ValueOfBool(true).MapKey(), MapKey{}},
+		{MapKey{}, MapKey{}},
+	}
+	for _, p := range mismatched {
+		for _, f := range []func(MapKey) bool{p[0].Less, p[0].Equal} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("comparing MapKey(%v) with MapKey(%v) did not panic", p[0], p[1])
+					}
+				}()
+				f(p[1])
+			}()
+		}
+	}
+}
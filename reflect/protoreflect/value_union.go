@@ -7,6 +7,7 @@ package protoreflect
 import (
 	"fmt"
 	"math"
+	"strconv"
 )
 
 // Value is a union where only one Go type may be set at a time.
@@ -192,6 +193,134 @@ func (v Value) IsValid() bool {
 	return v.typ != nilType
 }
 
+// IsZero reports whether v holds the zero value of a scalar type, namely
+// false, a numeric zero, an empty string, empty bytes, or enum number zero.
+// These are the values omitted when marshaling a field with implicit presence.
+// Consistent with that, a floating-point negative zero is not zero.
+//
+// It reports false for an invalid value and for any composite value.
+func (v Value) IsZero() bool {
+	switch v.typ {
+	case boolType, int32Type, int64Type, uint32Type, uint64Type, float32Type, float64Type, enumType:
+		return v.num == 0
+	case stringType:
+		return len(v.getString()) == 0
+	case bytesType:
+		return len(v.getBytes()) == 0
+	default:
+		return false
+	}
+}
+
+// IsScalar reports whether v holds a scalar, namely a bool, int32, int64,
+// uint32, uint64, float32, float64, string, []byte, or [EnumNumber].
+//
+// For any valid value, exactly one of IsScalar, IsMessage, IsList, and IsMap
+// reports true. All of them report false for an invalid value.
+func (v Value) IsScalar() bool {
+	switch v.typ {
+	case boolType, int32Type, int64Type, uint32Type, uint64Type, float32Type, float64Type, stringType, bytesType, enumType:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsMessage reports whether v holds a [Message],
+// in which case [Value.Message] does not panic.
+func (v Value) IsMessage() bool {
+	_, ok := v.getComposite().(Message)
+	return ok
+}
+
+// IsList reports whether v holds a [List],
+// in which case [Value.List] does not panic.
+func (v Value) IsList() bool {
+	_, ok := v.getComposite().(List)
+	return ok
+}
+
+// IsMap reports whether v holds a [Map],
+// in which case [Value.Map] does not panic.
+func (v Value) IsMap() bool {
+	_, ok := v.getComposite().(Map)
+	return ok
+}
+
+// getComposite returns the Message, List, or Map stored in v,
+// or nil if v is invalid or holds a scalar.
+func (v Value) getComposite() interface{} {
+	if !v.IsValid() || v.IsScalar() {
+		return nil
+	}
+	return v.getIface()
+}
+
+// ConvertTo converts a scalar value to the Go type used to represent
+// the given kind (see [Value]), reporting false if the conversion would
+// change the value. Only conversions within the same family are supported:
+// between int32 and int64, between uint32 and uint64, and between float32
+// and float64. Narrowing conversions succeed only if the value fits,
+// and a float64 is narrowed only if it is exactly representable as a float32
+// (infinities and NaN included).
+// Values that already have the appropriate Go type are returned unchanged.
+// It reports false for any other conversion and for invalid or
+// composite values.
+func (v Value) ConvertTo(k Kind) (Value, bool) {
+	switch k {
+	case BoolKind:
+		if v.typ == boolType {
+			return v, true
+		}
+	case EnumKind:
+		if v.typ == enumType {
+			return v, true
+		}
+	case StringKind:
+		if v.typ == stringType {
+			return v, true
+		}
+	case BytesKind:
+		if v.typ == bytesType {
+			return v, true
+		}
+	case Int32Kind, Sint32Kind, Sfixed32Kind:
+		if v.typ != int32Type && v.typ != int64Type {
+			return Value{}, false
+		}
+		if x := v.Int(); x == int64(int32(x)) {
+			return ValueOfInt32(int32(x)), true
+		}
+	case Int64Kind, Sint64Kind, Sfixed64Kind:
+		if v.typ == int32Type || v.typ == int64Type {
+			return ValueOfInt64(v.Int()), true
+		}
+	case Uint32Kind, Fixed32Kind:
+		if v.typ != uint32Type && v.typ != uint64Type {
+			return Value{}, false
+		}
+		if x := v.Uint(); x == uint64(uint32(x)) {
+			return ValueOfUint32(uint32(x)), true
+		}
+	case Uint64Kind, Fixed64Kind:
+		if v.typ == uint32Type || v.typ == uint64Type {
+			return ValueOfUint64(v.Uint()), true
+		}
+	case FloatKind:
+		if v.typ != float32Type && v.typ != float64Type {
+			return Value{}, false
+		}
+		if x := v.Float(); float64(float32(x)) == x || math.IsNaN(x) {
+			return ValueOfFloat32(float32(x)), true
+		}
+	case DoubleKind:
+		if v.typ == float32Type || v.typ == float64Type {
+			return ValueOfFloat64(v.Float()), true
+		}
+	}
+	return Value{}, false
+}
+
 // Interface returns v as an interface{}.
 //
 // Invariant: v == ValueOf(v).Interface()
@@ -317,6 +446,54 @@ func (v Value) String() string {
 	}
 }
 
+// DebugString returns a concise, human-readable rendering of v
+// for use in logs and test failure messages. The output is unstable
+// and must not be parsed.
+//
+// Scalars are rendered as literals, with strings quoted and enums shown as
+// enum(N). Bytes are rendered with their length and a hex prefix of at most
+// 16 bytes. Composite values are summarized without recursing into them:
+// messages as <Message full.Name>, lists as [len N], and maps as {len N}.
+// An invalid value is rendered as <null>.
+func (v Value) DebugString() string {
+	const maxBytes = 16
+	switch v.typ {
+	case nilType:
+		return "<null>"
+	case boolType:
+		return strconv.FormatBool(v.Bool())
+	case int32Type, int64Type:
+		return strconv.FormatInt(v.Int(), 10)
+	case uint32Type, uint64Type:
+		return strconv.FormatUint(v.Uint(), 10)
+	case float32Type:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case float64Type:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case stringType:
+		return strconv.Quote(v.getString())
+	case bytesType:
+		b := v.getBytes()
+		if len(b) > maxBytes {
+			return fmt.Sprintf("bytes[len %d] %x...", len(b), b[:maxBytes])
+		}
+		return fmt.Sprintf("bytes[len %d] %x", len(b), b)
+	case enumType:
+		return "enum(" + strconv.FormatInt(int64(v.Enum()), 10) + ")"
+	default:
+		switch x := v.getIface().(type) {
+		case Message:
+			return "<Message " + string(x.Descriptor().FullName()) + ">"
+		case List:
+			return "[len " + strconv.Itoa(x.Len()) + "]"
+		case Map:
+			return "{len " + strconv.Itoa(x.Len()) + "}"
+		default:
+			return fmt.Sprintf("<unknown: %T>", x)
+		}
+	}
+}
+
 // Bytes returns v as a []byte and panics if the type is not a []byte.
 func (v Value) Bytes() []byte {
 	switch v.typ {
@@ -337,6 +514,55 @@ func (v Value) Enum() EnumNumber {
 	}
 }
 
+// TryBool returns v as a bool and reports whether the type is a bool.
+func (v Value) TryBool() (bool, bool) {
+	if v.typ != boolType {
+		return false, false
+	}
+	return v.num > 0, true
+}
+
+// TryInt returns v as a int64 and reports whether the type is a int32 or int64.
+func (v Value) TryInt() (int64, bool) {
+	if v.typ != int32Type && v.typ != int64Type {
+		return 0, false
+	}
+	return int64(v.num), true
+}
+
+// TryUint returns v as a uint64 and reports whether the type is a uint32 or uint64.
+func (v Value) TryUint() (uint64, bool) {
+	if v.typ != uint32Type && v.typ != uint64Type {
+		return 0, false
+	}
+	return uint64(v.num), true
+}
+
+// TryFloat returns v as a float64 and reports whether the type is a float32 or float64.
+func (v Value) TryFloat() (float64, bool) {
+	if v.typ != float32Type && v.typ != float64Type {
+		return 0, false
+	}
+	return math.Float64frombits(uint64(v.num)), true
+}
+
+// TryString returns v as a string and reports whether the type is a string.
+// Unlike [Value.String], it does not format values of other types.
+func (v Value) TryString() (string, bool) {
+	if v.typ != stringType {
+		return "", false
+	}
+	return v.getString(), true
+}
+
+// TryBytes returns v as a []byte and reports whether the type is a []byte.
+func (v Value) TryBytes() ([]byte, bool) {
+	if v.typ != bytesType {
+		return nil, false
+	}
+	return v.getBytes(), true
+}
+
 // Message returns v as a [Message] and panics if the type is not a [Message].
 func (v Value) Message() Message {
 	switch vi := v.getIface().(type) {
@@ -368,12 +594,15 @@ func (v Value) Map() Map {
 }
 
 // MapKey returns v as a [MapKey] and panics for invalid [MapKey] types.
+// Only bool, integer, and string values may be used as map keys;
+// the panic message names the type of any other value.
 func (v Value) MapKey() MapKey {
 	switch v.typ {
 	case boolType, int32Type, int64Type, uint32Type, uint64Type, stringType:
 		return MapKey(v)
 	default:
-		panic(v.panicMessage("map key"))
+		panic(fmt.Sprintf("type mismatch: cannot use %v value as map key; "+
+			"map keys must be bool, int32, int64, uint32, uint64, or string", v.typeName()))
 	}
 }
 
@@ -436,3 +665,64 @@ func (k MapKey) String() string {
 func (k MapKey) Value() Value {
 	return Value(k)
 }
+
+// Equal reports whether k and y hold the same key.
+// It panics if k and y are of different types or if either is invalid.
+func (k MapKey) Equal(y MapKey) bool {
+	k.checkComparable(y)
+	return Value(k).Equal(Value(y))
+}
+
+// Less reports whether k is ordered before y:
+// false before true, numeric keys in ascending numeric order,
+// and string keys in lexicographical order of their UTF-8 bytes.
+// It panics if k and y are of different types or if either is invalid.
+func (k MapKey) Less(y MapKey) bool {
+	k.checkComparable(y)
+	switch k.typ {
+	case boolType:
+		return !k.Bool() && y.Bool()
+	case int32Type, int64Type:
+		return k.Int() < y.Int()
+	case uint32Type, uint64Type:
+		return k.Uint() < y.Uint()
+	default:
+		return k.String() < y.String()
+	}
+}
+
+// checkComparable panics unless k and y are valid keys of the same type.
+func (k MapKey) checkComparable(y MapKey) {
+	switch k.typ {
+	case boolType, int32Type, int64Type, uint32Type, uint64Type, stringType:
+	default:
+		panic(Value(k).panicMessage("map key"))
+	}
+	if k.typ != y.typ {
+		panic(fmt.Sprintf("type mismatch: cannot compare %v map key with %v map key", Value(k).typeName(), Value(y).typeName()))
+	}
+}
+
+// CanonicalString returns a string representation of k that is prefixed
+// with its Go type, such as "i64:42" or "s:hello". Keys of different types
+// never produce the same string, even if they hold the same numeric value,
+// making the result suitable as a key in a Go map[string].
+// It panics if k is invalid.
+func (k MapKey) CanonicalString() string {
+	switch k.typ {
+	case boolType:
+		return "b:" + strconv.FormatBool(k.Bool())
+	case int32Type:
+		return "i32:" + strconv.FormatInt(k.Int(), 10)
+	case int64Type:
+		return "i64:" + strconv.FormatInt(k.Int(), 10)
+	case uint32Type:
+		return "u32:" + strconv.FormatUint(k.Uint(), 10)
+	case uint64Type:
+		return "u64:" + strconv.FormatUint(k.Uint(), 10)
+	case stringType:
+		return "s:" + k.String()
+	default:
+		panic(Value(k).panicMessage("map key"))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect
+
+import (
+	"sort"
+)
+
+// Walk performs a depth-first traversal of every populated field of m,
+// calling visit for each scalar value found in m or in any message nested
+// within it. Extension fields are visited; unknown fields are not.
+//
+// Fields of each message are visited in ascending order of field number.
+// Singular message fields, message elements of lists, and message values
+// of maps are descended into, rather than being passed to visit.
+//
+//   - A singular scalar field is visited once with its value.
+//
+//   - A list of scalars is visited once per element, in order,
+//     with the list's field descriptor and the element value.
+//
+//   - A map with scalar values is visited once per entry,
+//     in the order of [MapKeys], with the descriptor of the map value
+//     (see [FieldDescriptor.MapValue]) and the entry value.
+//
+// The path holds the field numbers leading from m to the visited value,
+// ending with the number of the field that holds it. List indexes and map
+// keys do not contribute to the path, so every element of a list and every
+// value of a map shares the same path. The path slice is reused between
+// calls and must be copied if it is retained after visit returns.
+func Walk(m Message, visit func(path []FieldNumber, fd FieldDescriptor, v Value)) {
+	walkMessage(nil, m, visit)
+}
+
+func walkMessage(path []FieldNumber, m Message, visit func([]FieldNumber, FieldDescriptor, Value)) {
+	var fds []FieldDescriptor
+	m.Range(func(fd FieldDescriptor, _ Value) bool {
+		fds = append(fds, fd)
+		return true
+	})
+	sort.Slice(fds, func(i, j int) bool { return fds[i].Number() < fds[j].Number() })
+	for _, fd := range fds {
+		path := append(path, fd.Number())
+		v := m.Get(fd)
+		switch {
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				walkValue(path, fd, l.Get(i), visit)
+			}
+		case fd.IsMap():
+			mm := v.Map()
+			for _, k := range MapKeys(mm) {
+				walkValue(path, fd.MapValue(), mm.Get(k), visit)
+			}
+		default:
+			walkValue(path, fd, v, visit)
+		}
+	}
+}
+
+func walkValue(path []FieldNumber, fd FieldDescriptor, v Value, visit func([]FieldNumber, FieldDescriptor, Value)) {
+	if fd.Message() != nil {
+		walkMessage(path, v.Message(), visit)
+		return
+	}
+	visit(path, fd, v)
+}
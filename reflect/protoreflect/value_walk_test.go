@@ -0,0 +1,76 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoreflect_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestWalk(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A:           proto.Int32(2),
+			Corecursive: &testpb.TestAllTypes{OptionalInt32: proto.Int32(3)},
+		},
+		RepeatedInt32: []int32{4, 5},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+			{A: proto.Int32(6)},
+			{},
+			{A: proto.Int32(7)},
+		},
+		MapInt32Int32: map[int32]int32{2: 9, 1: 8},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"b": {A: proto.Int32(11)},
+			"a": {A: proto.Int32(10)},
+		},
+	}
+
+	var got []string
+	protoreflect.Walk(m.ProtoReflect(), func(path []protoreflect.FieldNumber, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		got = append(got, fmt.Sprintf("%v %v=%v", path, fd.Name(), v))
+	})
+	want := []string{
+		"[1] optional_int32=1",
+		"[18 1] a=2",
+		"[18 2 1] optional_int32=3",
+		"[31] repeated_int32=4",
+		"[31] repeated_int32=5",
+		"[48 1] a=6",
+		"[48 1] a=7",
+		"[56] value=8",
+		"[56] value=9",
+		"[71 1] a=10",
+		"[71 1] a=11",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk visited:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWalkExtensions(t *testing.T) {
+	m := &testpb.TestAllExtensions{}
+	proto.SetExtension(m, testpb.E_OptionalInt32, int32(1))
+	proto.SetExtension(m, testpb.E_OptionalNestedMessage, &testpb.TestAllExtensions_NestedMessage{A: proto.Int32(2)})
+
+	var got []string
+	protoreflect.Walk(m.ProtoReflect(), func(path []protoreflect.FieldNumber, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+		got = append(got, fmt.Sprintf("%v %v=%v", path, fd.FullName(), v))
+	})
+	want := []string{
+		"[1] goproto.proto.test.optional_int32=1",
+		"[18 1] goproto.proto.test.TestAllExtensions.NestedMessage.a=2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk visited:\n%q\nwant:\n%q", got, want)
+	}
+}
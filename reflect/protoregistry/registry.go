@@ -546,6 +546,66 @@ func (r *Types) RegisterExtension(xt protoreflect.ExtensionType) error {
 		defer globalMutex.Unlock()
 	}
 
+	if err := r.checkExtension(xd, xt); err != nil {
+		return err
+	}
+	r.addExtension(xd, xt)
+	return nil
+}
+
+// RegisterExtensions registers all of the provided extension types.
+//
+// The whole batch is checked for conflicts, both against types already in
+// the registry and among the provided extension types themselves,
+// before any of them is registered. If a conflict occurs, none of the
+// types are registered and an error identifying the conflicting
+// extension is returned.
+func (r *Types) RegisterExtensions(xts []protoreflect.ExtensionType) error {
+	// Under rare circumstances getting the descriptor might recursively
+	// examine the registry, so fetch them before locking.
+	xds := make([]protoreflect.ExtensionTypeDescriptor, len(xts))
+	for i, xt := range xts {
+		xds[i] = xt.TypeDescriptor()
+	}
+
+	if r == GlobalTypes {
+		globalMutex.Lock()
+		defer globalMutex.Unlock()
+	}
+
+	type extensionKey struct {
+		message protoreflect.FullName
+		field   protoreflect.FieldNumber
+	}
+	byNumber := make(map[extensionKey]protoreflect.ExtensionType)
+	byName := make(map[protoreflect.FullName]protoreflect.ExtensionType)
+	for i, xt := range xts {
+		xd := xds[i]
+		key := extensionKey{xd.ContainingMessage().FullName(), xd.Number()}
+		if prev := byNumber[key]; prev != nil {
+			err := errors.New("extension number %d is provided more than once for message %v", key.field, key.message)
+			return amendErrorWithCaller(err, prev, xt)
+		}
+		if prev := byName[xd.FullName()]; prev != nil {
+			err := errors.New("extension %v is provided more than once", xd.FullName())
+			return amendErrorWithCaller(err, prev, xt)
+		}
+		byNumber[key] = xt
+		byName[xd.FullName()] = xt
+
+		if err := r.checkExtension(xd, xt); err != nil {
+			return err
+		}
+	}
+	for i, xt := range xts {
+		r.addExtension(xds[i], xt)
+	}
+	return nil
+}
+
+// checkExtension reports an error if registering xt would conflict
+// with a type already in the registry.
+func (r *Types) checkExtension(xd protoreflect.ExtensionTypeDescriptor, xt protoreflect.ExtensionType) error {
 	field := xd.Number()
 	message := xd.ContainingMessage().FullName()
 	if prev := r.extensionsByMessage[message][field]; prev != nil {
@@ -556,9 +616,26 @@ func (r *Types) RegisterExtension(xt protoreflect.ExtensionType) error {
 		}
 	}
 
-	if err := r.register("extension", xd, xt); err != nil {
-		return err
+	name := xd.FullName()
+	if prev := r.typesByName[name]; prev != nil {
+		err := errors.New("extension %v is already registered", name)
+		err = amendErrorWithCaller(err, prev, xt)
+		if !(r == GlobalTypes && ignoreConflict(xd, err)) {
+			return err
+		}
 	}
+	return nil
+}
+
+// addExtension adds xt to the registry, which must have been checked for
+// conflicts with checkExtension.
+func (r *Types) addExtension(xd protoreflect.ExtensionTypeDescriptor, xt protoreflect.ExtensionType) {
+	field := xd.Number()
+	message := xd.ContainingMessage().FullName()
+	if r.typesByName == nil {
+		r.typesByName = make(typesByName)
+	}
+	r.typesByName[xd.FullName()] = xt
 	if r.extensionsByMessage == nil {
 		r.extensionsByMessage = make(extensionsByMessage)
 	}
@@ -567,7 +644,6 @@ func (r *Types) RegisterExtension(xt protoreflect.ExtensionType) error {
 	}
 	r.extensionsByMessage[message][field] = xt
 	r.numExtensions++
-	return nil
 }
 
 func (r *Types) register(kind string, desc protoreflect.Descriptor, typ interface{}) error {
@@ -587,6 +663,28 @@ func (r *Types) register(kind string, desc protoreflect.Descriptor, typ interfac
 	return nil
 }
 
+// ContainsExtension reports whether registering the provided extension type
+// would conflict with a type already in the registry. This is the case if
+// another extension with the same number is registered on the same message,
+// or if any type with the same full name is registered.
+//
+// It may be used to guard calls to [Types.RegisterExtension].
+func (r *Types) ContainsExtension(xt protoreflect.ExtensionType) bool {
+	if r == nil {
+		return false
+	}
+	xd := xt.TypeDescriptor()
+
+	if r == GlobalTypes {
+		globalMutex.RLock()
+		defer globalMutex.RUnlock()
+	}
+	if r.extensionsByMessage[xd.ContainingMessage().FullName()][xd.Number()] != nil {
+		return true
+	}
+	return r.typesByName[xd.FullName()] != nil
+}
+
 // FindEnumByName looks up an enum by its full name.
 // E.g., "google.protobuf.Field.Kind".
 //
@@ -841,6 +939,43 @@ func (r *Types) RangeExtensionsByMessage(message protoreflect.FullName, f func(p
 	}
 }
 
+// Clone returns a new registry containing the same enum, message, and
+// extension types as r. Registering types in the clone does not affect r
+// and vice versa.
+//
+// The types themselves are not copied since they are immutable;
+// only the registry's index of the types is duplicated.
+func (r *Types) Clone() *Types {
+	if r == nil {
+		return new(Types)
+	}
+	if r == GlobalTypes {
+		globalMutex.RLock()
+		defer globalMutex.RUnlock()
+	}
+	c := &Types{
+		numEnums:      r.numEnums,
+		numMessages:   r.numMessages,
+		numExtensions: r.numExtensions,
+	}
+	if r.typesByName != nil {
+		c.typesByName = make(typesByName, len(r.typesByName))
+		for name, typ := range r.typesByName {
+			c.typesByName[name] = typ
+		}
+	}
+	if r.extensionsByMessage != nil {
+		c.extensionsByMessage = make(extensionsByMessage, len(r.extensionsByMessage))
+		for message, xts := range r.extensionsByMessage {
+			c.extensionsByMessage[message] = make(extensionsByNumber, len(xts))
+			for field, xt := range xts {
+				c.extensionsByMessage[message][field] = xt
+			}
+		}
+	}
+	return c
+}
+
 func typeName(t interface{}) string {
 	switch t.(type) {
 	case protoreflect.EnumType:
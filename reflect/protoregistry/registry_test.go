@@ -20,6 +20,7 @@ import (
 
 	testpb "google.golang.org/protobuf/internal/testprotos/registry"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func mustMakeFile(s string) protoreflect.FileDescriptor {
@@ -653,3 +654,147 @@ func TestTypes(t *testing.T) {
 		}
 	})
 }
+
+func TestTypesClone(t *testing.T) {
+	mt1 := pimpl.Export{}.MessageTypeOf(&testpb.Message1{})
+	xt1 := testpb.E_StringField
+	xt2 := testpb.E_Message4_MessageField
+	registry := new(protoregistry.Types)
+	if err := registry.RegisterMessage(mt1); err != nil {
+		t.Fatalf("registry.RegisterMessage(%v) returns unexpected error: %v", mt1.Descriptor().FullName(), err)
+	}
+	if err := registry.RegisterExtension(xt1); err != nil {
+		t.Fatalf("registry.RegisterExtension(%v) returns unexpected error: %v", xt1.TypeDescriptor().FullName(), err)
+	}
+
+	clone := registry.Clone()
+	if got, want := clone.NumMessages(), registry.NumMessages(); got != want {
+		t.Errorf("clone.NumMessages() = %v, want %v", got, want)
+	}
+	if got, want := clone.NumExtensions(), registry.NumExtensions(); got != want {
+		t.Errorf("clone.NumExtensions() = %v, want %v", got, want)
+	}
+	if got, err := clone.FindExtensionByNumber("testprotos.Message1", 11); err != nil || got != xt1 {
+		t.Errorf("clone.FindExtensionByNumber(testprotos.Message1, 11) = (%v, %v), want (%v, nil)", got, err, xt1)
+	}
+
+	if err := clone.RegisterExtension(xt2); err != nil {
+		t.Fatalf("clone.RegisterExtension(%v) returns unexpected error: %v", xt2.TypeDescriptor().FullName(), err)
+	}
+	if got, want := clone.NumExtensions(), 2; got != want {
+		t.Errorf("clone.NumExtensions() = %v, want %v", got, want)
+	}
+	if got, want := registry.NumExtensions(), 1; got != want {
+		t.Errorf("registry.NumExtensions() = %v, want %v", got, want)
+	}
+	if _, err := registry.FindExtensionByNumber("testprotos.Message1", 21); err != protoregistry.NotFound {
+		t.Errorf("registry.FindExtensionByNumber(testprotos.Message1, 21) got error: %v, want NotFound error", err)
+	}
+}
+
+func TestTypesContainsExtension(t *testing.T) {
+	fd := mustMakeFile(`
+		syntax:  "proto2"
+		name:    "conflict.proto"
+		package: "testprotos"
+		message_type: [{name:"Message1" extension_range:[{start:10 end:100}]}]
+		extension: [
+			{name:"other_field"  number:11 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"string_field" number:15 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"fresh_field"  number:16 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"}
+		]
+	`)
+	sameNumber := dynamicpb.NewExtensionType(fd.Extensions().ByName("other_field"))
+	sameName := dynamicpb.NewExtensionType(fd.Extensions().ByName("string_field"))
+	fresh := dynamicpb.NewExtensionType(fd.Extensions().ByName("fresh_field"))
+
+	registry := new(protoregistry.Types)
+	if registry.ContainsExtension(testpb.E_StringField) {
+		t.Errorf("ContainsExtension(%v) = true on empty registry, want false", testpb.E_StringField.TypeDescriptor().FullName())
+	}
+	if err := registry.RegisterExtension(testpb.E_StringField); err != nil {
+		t.Fatalf("registry.RegisterExtension(%v) returns unexpected error: %v", testpb.E_StringField.TypeDescriptor().FullName(), err)
+	}
+
+	tests := []struct {
+		name string
+		xt   protoreflect.ExtensionType
+		want bool
+	}{
+		{"registered", testpb.E_StringField, true},
+		{"conflict by number", sameNumber, true},
+		{"conflict by name", sameName, true},
+		{"no conflict", fresh, false},
+	}
+	for _, tt := range tests {
+		if got := registry.ContainsExtension(tt.xt); got != tt.want {
+			t.Errorf("%v: ContainsExtension(%v) = %v, want %v", tt.name, tt.xt.TypeDescriptor().FullName(), got, tt.want)
+		}
+		if err := registry.Clone().RegisterExtension(tt.xt); (err != nil) != tt.want {
+			t.Errorf("%v: RegisterExtension(%v) = %v, want error? %v", tt.name, tt.xt.TypeDescriptor().FullName(), err, tt.want)
+		}
+	}
+}
+
+func TestTypesRegisterExtensions(t *testing.T) {
+	fd := mustMakeFile(`
+		syntax:  "proto2"
+		name:    "batch.proto"
+		package: "testprotos"
+		message_type: [{name:"Message1" extension_range:[{start:10 end:100}]}]
+		extension: [
+			{name:"other_field"  number:11 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"string_field" number:15 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"fresh_field"  number:16 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"fresh_field2" number:17 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"},
+			{name:"dup_number"   number:17 label:LABEL_OPTIONAL type:TYPE_STRING extendee:".testprotos.Message1"}
+		]
+	`)
+	xt := func(name protoreflect.Name) protoreflect.ExtensionType {
+		return dynamicpb.NewExtensionType(fd.Extensions().ByName(name))
+	}
+	fresh, fresh2 := xt("fresh_field"), xt("fresh_field2")
+
+	newRegistry := func() *protoregistry.Types {
+		registry := new(protoregistry.Types)
+		if err := registry.RegisterExtension(testpb.E_StringField); err != nil {
+			t.Fatalf("registry.RegisterExtension(%v) returns unexpected error: %v", testpb.E_StringField.TypeDescriptor().FullName(), err)
+		}
+		return registry
+	}
+
+	registry := newRegistry()
+	if err := registry.RegisterExtensions([]protoreflect.ExtensionType{fresh, fresh2}); err != nil {
+		t.Fatalf("RegisterExtensions returns unexpected error: %v", err)
+	}
+	if got, want := registry.NumExtensions(), 3; got != want {
+		t.Errorf("NumExtensions() = %d, want %d", got, want)
+	}
+	for _, xt := range []protoreflect.ExtensionType{fresh, fresh2} {
+		if got, err := registry.FindExtensionByName(xt.TypeDescriptor().FullName()); err != nil || got != xt {
+			t.Errorf("FindExtensionByName(%v) = %v, %v, want %v", xt.TypeDescriptor().FullName(), got, err, xt)
+		}
+	}
+
+	tests := []struct {
+		name string
+		xts  []protoreflect.ExtensionType
+	}{
+		{"registered conflict by number", []protoreflect.ExtensionType{fresh, xt("other_field")}},
+		{"registered conflict by name", []protoreflect.ExtensionType{fresh, xt("string_field")}},
+		{"batch conflict by number", []protoreflect.ExtensionType{fresh, fresh2, xt("dup_number")}},
+		{"batch conflict by name", []protoreflect.ExtensionType{fresh, fresh2, fresh}},
+	}
+	for _, tt := range tests {
+		registry := newRegistry()
+		if err := registry.RegisterExtensions(tt.xts); err == nil {
+			t.Errorf("%v: RegisterExtensions succeeded, want error", tt.name)
+		}
+		if got, want := registry.NumExtensions(), 1; got != want {
+			t.Errorf("%v: NumExtensions() = %d after failed RegisterExtensions, want %d", tt.name, got, want)
+		}
+		if registry.ContainsExtension(fresh) {
+			t.Errorf("%v: %v was registered by failed RegisterExtensions", tt.name, fresh.TypeDescriptor().FullName())
+		}
+	}
+}
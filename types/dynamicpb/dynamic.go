@@ -9,6 +9,7 @@ import (
 	"math"
 
 	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoiface"
 	"google.golang.org/protobuf/runtime/protoimpl"
@@ -85,6 +86,17 @@ func NewMessage(desc protoreflect.MessageDescriptor) *Message {
 	}
 }
 
+// NewMessageFromBytes creates a new message with the provided descriptor
+// and populates it by unmarshaling the wire-format bytes b.
+// It is equivalent to calling [proto.Unmarshal] on the result of [NewMessage].
+func NewMessageFromBytes(desc protoreflect.MessageDescriptor, b []byte) (*Message, error) {
+	m := NewMessage(desc)
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ProtoMessage implements the legacy message interface.
 func (m *Message) ProtoMessage() {}
 
@@ -440,6 +452,12 @@ func (x *dynamicList) Truncate(n int) {
 	x.list = x.list[:n]
 }
 
+func (x *dynamicList) Grow(n int) {
+	if cap(x.list)-len(x.list) < n {
+		x.list = append(make([]protoreflect.Value, 0, len(x.list)+n), x.list...)
+	}
+}
+
 func (x *dynamicList) NewElement() protoreflect.Value {
 	return newListEntry(x.desc)
 }
@@ -479,9 +497,19 @@ func (x *dynamicMap) NewValue() protoreflect.Value {
 	}
 	return x.desc.MapValue().Default()
 }
+func (x *dynamicMap) NewKey() protoreflect.MapKey {
+	return x.desc.MapKey().Default().MapKey()
+}
 func (x *dynamicMap) IsValid() bool {
 	return x.mapv != nil
 }
+func (x *dynamicMap) Grow(n int) {
+	// Only an empty map is pre-sized; see mapReflect.Grow.
+	if x.mapv == nil || len(x.mapv) != 0 {
+		return
+	}
+	x.mapv = make(map[interface{}]protoreflect.Value, n)
+}
 
 func (x *dynamicMap) Range(f func(protoreflect.MapKey, protoreflect.Value) bool) {
 	for k, v := range x.mapv {
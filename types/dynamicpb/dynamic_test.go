@@ -79,3 +79,37 @@ func (extResolver) RangeExtensionsByMessage(message protoreflect.FullName, f fun
 		return f(dynamicpb.NewExtensionType(xt.TypeDescriptor().Descriptor()))
 	})
 }
+
+func TestNewMessageFromBytes(t *testing.T) {
+	want := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalString:        proto.String("hello"),
+		RepeatedInt64:         []int64{2, 3},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(4)},
+	}
+	b, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	md := want.ProtoReflect().Descriptor()
+	m, err := dynamicpb.NewMessageFromBytes(md, b)
+	if err != nil {
+		t.Fatalf("NewMessageFromBytes() error: %v", err)
+	}
+	if got := m.Get(md.Fields().ByName("optional_int32")).Int(); got != 1 {
+		t.Errorf("optional_int32 = %v, want 1", got)
+	}
+	if got := m.Get(md.Fields().ByName("optional_string")).String(); got != "hello" {
+		t.Errorf("optional_string = %q, want %q", got, "hello")
+	}
+	if got := m.Get(md.Fields().ByName("repeated_int64")).List().Len(); got != 2 {
+		t.Errorf("len(repeated_int64) = %v, want 2", got)
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("NewMessageFromBytes() = %v, want %v", m, want)
+	}
+
+	if _, err := dynamicpb.NewMessageFromBytes(md, b[:len(b)-1]); err == nil {
+		t.Errorf("NewMessageFromBytes(truncated) = nil error, want error")
+	}
+}